@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/golang-jwt/jwt"
 )
 
 // TokenStore defines an interface for JWT storage solutions. It allows clients to implement their
@@ -25,46 +29,281 @@ type TokenStore interface {
 	Get(ctx context.Context) string
 }
 
+// TokenStoreV2 is an alternative to TokenStore for storage backends which can fail, such as Redis or a database.
+// Unlike TokenStore, it can report backend failures, so a broken store isn't mistaken for a missing token, which
+// would otherwise trigger a new authentication on every request. Get returns a zero Token and no error when no
+// token is stored. Delete is called to invalidate a token which was rejected by CZDS.
+// When provided via TokenStoreV2Opt, it is preferred over any TokenStore. Existing TokenStore implementations can
+// be used where a TokenStoreV2 is expected with AdaptTokenStore.
+type TokenStoreV2 interface {
+	Get(ctx context.Context) (Token, error)
+	Save(ctx context.Context, token Token) error
+	Delete(ctx context.Context) error
+}
+
 type authTransport struct {
+	base               http.RoundTripper
 	httpClient         *http.Client
-	email              string
-	password           string
-	tokenStore         TokenStore
+	credentials        CredentialsProvider
+	tokenStore         TokenStoreV2
+	locker             TokenLocker
 	accountsAPIBaseURL string
+	refreshSkew        time.Duration
+	limiter            *authLimiter
+	authTimeout        time.Duration
+
+	mu       sync.Mutex
+	inflight *tokenFetch
 }
 
+// tokenFetch is a JWT fetch in progress, shared by every request waiting for a new JWT.
+type tokenFetch struct {
+	done     chan struct{}
+	cancel   context.CancelFunc
+	lead     time.Duration
+	rejected string
+	waiters  int
+	token    string
+	err      error
+}
+
+// RoundTrip sends the request with the stored JWT, fetching a new one if it doesn't exist, is invalid or has
+// expired. If the server still rejects the JWT with HTTP 401, e.g. because it was revoked early, the stored JWT
+// is invalidated and the request is replayed once with a freshly fetched JWT. A JWT stored by another request
+// in the meantime is reused rather than invalidated. Should the replayed request be rejected too, an *APIError
+// describing its response is returned, wrapping ErrTokenRejected.
 func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	token := a.tokenStore.Get(req.Context())
-	if !isTokenValid(token) {
-		var err error
-		token, err = a.fetchJWT()
+	ctx := req.Context()
+
+	stored, err := a.tokenStore.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JWT from store: %w", err)
+	}
+
+	token := stored.Value
+	if !stored.valid(a.refreshSkew) {
+		if token, err = a.refreshToken(ctx, a.refreshSkew, ""); err != nil {
+			return nil, &tokenRefreshError{err: err}
+		}
+	}
+
+	getBody, err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.send(req, getBody, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	discardBody(resp)
+
+	if token, err = a.refreshToken(ctx, a.refreshSkew, token); err != nil {
+		return nil, &tokenRefreshError{err: err}
+	}
+
+	resp, err = a.send(req, getBody, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	apiErr := newAPIError(req, resp)
+	apiErr.Err = ErrTokenRejected
+	return nil, apiErr
+}
+
+// tokenRefreshError wraps a failure to obtain a JWT, so the retry policy doesn't mistake it for a transient failure
+// of the CZDS request and authenticate again on every attempt.
+type tokenRefreshError struct {
+	err error
+}
+
+func (e *tokenRefreshError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tokenRefreshError) Unwrap() error {
+	return e.err
+}
+
+// forceRefresh is the lead passed to refreshToken to fetch a new JWT even if the stored one is valid.
+const forceRefresh time.Duration = math.MaxInt64
+
+// refreshToken fetches and stores a new JWT, unless the stored one has meanwhile been replaced by one which
+// doesn't expire within lead. A rejected JWT, if not empty, is invalidated and replaced even if it hasn't expired,
+// provided it is still the stored one. Concurrent calls are coalesced, so only a single authentication request is
+// in flight at a time and every caller shares its result, unless a caller requires a longer lead than the fetch in
+// flight or rejected a JWT the fetch in flight doesn't replace.
+// Each caller stops waiting as soon as its context is done, while the authentication request itself is cancelled
+// once no caller is left waiting for it or the authentication timeout elapses.
+func (a *authTransport) refreshToken(ctx context.Context, lead time.Duration, rejected string) (string, error) {
+	a.mu.Lock()
+	f := a.inflight
+	if f == nil || f.lead < lead || (rejected != "" && f.rejected != rejected) {
+		fetchCtx, cancel := a.fetchContext(ctx)
+
+		f = &tokenFetch{done: make(chan struct{}), cancel: cancel, lead: lead, rejected: rejected}
+		a.inflight = f
+		go a.runFetch(fetchCtx, f)
+	}
+	f.waiters++
+	a.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.token, f.err
+	case <-ctx.Done():
+		a.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Callers arriving while the cancelled fetch winds down must start a new one rather than share its error.
+			f.cancel()
+			if a.inflight == f {
+				a.inflight = nil
+			}
+		}
+		a.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+// fetchContext returns the context for an authentication request, carrying the values of ctx but not its
+// cancellation, as the request is shared with other callers. It is bounded by the authentication timeout instead.
+func (a *authTransport) fetchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.authTimeout > 0 {
+		return context.WithTimeout(context.WithoutCancel(ctx), a.authTimeout)
+	}
+	return context.WithCancel(context.WithoutCancel(ctx))
+}
+
+func (a *authTransport) runFetch(ctx context.Context, f *tokenFetch) {
+	defer f.cancel()
+
+	f.token, f.err = a.fetchAndStoreToken(ctx, f.lead, f.rejected)
+
+	a.mu.Lock()
+	if a.inflight == f {
+		a.inflight = nil
+	}
+	a.mu.Unlock()
+	close(f.done)
+}
+
+func (a *authTransport) fetchAndStoreToken(ctx context.Context, lead time.Duration, rejected string) (string, error) {
+	if a.locker != nil {
+		unlock, err := a.locker.Lock(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch JWT: %w", err)
+			return "", fmt.Errorf("failed to acquire token lock: %w", err)
 		}
+		defer unlock()
+	}
 
-		if !isTokenValid(token) {
-			return nil, fmt.Errorf("fetched JWT is not valid: %w", err)
+	// A fetch which completed while this one was being set up, possibly in another process holding the token lock,
+	// may have already stored a new JWT.
+	stored, err := a.tokenStore.Get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get JWT from store: %w", err)
+	}
+	if rejected != "" && stored.Value == rejected {
+		if err := a.tokenStore.Delete(ctx); err != nil {
+			return "", fmt.Errorf("failed to invalidate JWT: %w", err)
 		}
+	} else if lead != forceRefresh && stored.valid(lead) {
+		return stored.Value, nil
+	}
 
-		if err := a.tokenStore.Save(req.Context(), token); err != nil {
-			return nil, fmt.Errorf("failed to store JWT: %w", err)
+	if a.limiter != nil {
+		if err := a.limiter.acquire(ctx); err != nil {
+			return "", err
 		}
 	}
 
-	req.Header.Add("Authorization", "Bearer "+token)
+	token, err := a.fetchJWT(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch JWT: %w", err)
+	}
+
+	fetched := Token{Value: token, ExpiresAt: tokenExpiry(token)}
+	if !fetched.valid(0) {
+		return "", errors.New("fetched JWT is not valid")
+	}
 
-	return http.DefaultTransport.RoundTrip(req)
+	if err := a.tokenStore.Save(ctx, fetched); err != nil {
+		return "", fmt.Errorf("failed to store JWT: %w", err)
+	}
+
+	return token, nil
+}
+
+// send sends a copy of the request authorised with the given JWT, leaving the original request untouched.
+func (a *authTransport) send(req *http.Request, getBody bodyGetter, token string) (*http.Response, error) {
+	authReq := req.Clone(req.Context())
+	if getBody != nil {
+		body, err := getBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		authReq.Body = body
+	}
+	authReq.Header.Set("Authorization", "Bearer "+token)
+
+	return a.base.RoundTrip(authReq)
 }
 
-func (a *authTransport) fetchJWT() (string, error) {
+// bodyGetter returns a fresh copy of a request body.
+type bodyGetter func() (io.ReadCloser, error)
+
+// rewindableBody returns a bodyGetter for the request body, so the request can be replayed. Bodies which can't be
+// rewound by the request itself are buffered in memory.
+func rewindableBody(req *http.Request) (bodyGetter, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to buffer request body: %w", err)
+	}
+
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}, nil
+}
+
+const maxErrorMessageSize = 1 << 10
+
+// readErrorMessage reads up to maxErrorMessageSize bytes of the response body and closes it.
+func readErrorMessage(resp *http.Response) string {
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+	return strings.TrimSpace(string(msg))
+}
+
+// discardBody drains and closes the response body, so the underlying connection can be reused.
+func discardBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorMessageSize))
+	_ = resp.Body.Close()
+}
+
+func (a *authTransport) fetchJWT(ctx context.Context) (string, error) {
 	type credentials struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
 	}
 
+	provided, err := a.credentials.Credentials(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credentials: %w", err)
+	}
+
 	creds := &credentials{
-		Username: a.email,
-		Password: a.password,
+		Username: provided.Email,
+		Password: provided.Password,
 	}
 
 	body := new(bytes.Buffer)
@@ -72,7 +311,7 @@ func (a *authTransport) fetchJWT() (string, error) {
 		return "", fmt.Errorf("failed to encode credentials for auth request body: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, a.accountsAPIBaseURL+"/authenticate", body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.accountsAPIBaseURL+"/authenticate", body)
 	if err != nil {
 		return "", fmt.Errorf("failed to create auth request: %w", err)
 	}
@@ -82,9 +321,10 @@ func (a *authTransport) fetchJWT() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("authentication request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("expected HTTP 200 response, got %d", resp.StatusCode)
+		return "", newAuthError(resp)
 	}
 
 	var auth authResponse
@@ -95,18 +335,108 @@ func (a *authTransport) fetchJWT() (string, error) {
 	return auth.AccessToken, nil
 }
 
-func isTokenValid(token string) bool {
-	parsedToken, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
-	if err != nil {
-		return false
+// newAuthError returns an *AuthError describing a failed authentication response, classifying the failure by its
+// status code so it can be matched with errors.Is against ErrInvalidCredentials, ErrAccountLocked,
+// ErrAuthRateLimited or ErrAuthServerFailure. A 403 means the account is locked or disabled, as does a 401 carrying
+// one of the known lockout messages, while any other 401 or a 400 means the credentials were rejected.
+func newAuthError(resp *http.Response) *AuthError {
+	authErr := &AuthError{
+		StatusCode: resp.StatusCode,
+		Message:    readErrorMessage(resp),
 	}
 
-	var expiresAt time.Time
-	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok {
-		if exp, ok := claims["exp"].(float64); ok {
-			expiresAt = time.Unix(int64(exp), 0)
+	var auth authResponse
+	if err := json.Unmarshal([]byte(authErr.Message), &auth); err == nil && auth.Message != "" {
+		authErr.Message = auth.Message
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		authErr.Err = ErrAuthRateLimited
+		authErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode == http.StatusUnauthorized && isAccountLockedMessage(authErr.Message):
+		authErr.Err = ErrAccountLocked
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusBadRequest:
+		authErr.Err = ErrInvalidCredentials
+	case resp.StatusCode >= http.StatusInternalServerError:
+		authErr.Err = ErrAuthServerFailure
+	}
+
+	return authErr
+}
+
+// accountLockedMessages are the messages the ICANN accounts API may reject authentication of a locked or disabled
+// account with when responding with a 401 rather than a 403. They are matched exactly, so a message merely
+// mentioning a lockout, such as a warning about the attempts remaining, isn't mistaken for one.
+var accountLockedMessages = []string{
+	"account locked",
+	"account disabled",
+	"your account has been locked",
+	"your account has been disabled",
+	"user account is locked",
+	"user account is disabled",
+}
+
+func isAccountLockedMessage(msg string) bool {
+	msg = strings.TrimRight(strings.TrimSpace(msg), ".!")
+	for _, locked := range accountLockedMessages {
+		if strings.EqualFold(msg, locked) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter parses the value of a Retry-After header, given either in seconds or as an HTTP date, returning
+// zero if it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
 		}
 	}
 
-	return time.Now().UTC().Before(expiresAt.UTC())
+	return 0
+}
+
+// refreshInBackground checks the stored JWT every interval until stop is closed, refreshing it once it expires
+// within twice the interval, or within the refresh skew if longer, so it is replaced before expiring even between
+// two checks. Failures are ignored, as the JWT is refreshed again on the next check or request.
+func (a *authTransport) refreshInBackground(interval time.Duration, stop <-chan struct{}) {
+	lead := max(a.refreshSkew, 2*interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if stored, err := a.tokenStore.Get(ctx); err == nil && !stored.valid(lead) {
+				_, _ = a.refreshToken(ctx, lead, "")
+			}
+		}
+	}
+}
+
+// tokenExpiry returns the expiry time held in the exp claim of the JWT, or the zero time if the token can't be
+// parsed or has no expiry.
+func tokenExpiry(token string) time.Time {
+	return newTokenInfo(Token{Value: token}).ExpiresAt
 }
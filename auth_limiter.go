@@ -0,0 +1,96 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AuthAttemptStore records authentication attempts, so the authentication budget configured with AuthRateLimit
+// can be enforced. Backing it by a shared store, such as Redis or a database, allows multiple processes to
+// cooperate on the same budget.
+type AuthAttemptStore interface {
+	// Reserve records an attempt made at now, provided fewer than limit attempts were recorded within the window
+	// ending at now. Otherwise, it records nothing and returns the time at which the next attempt is allowed.
+	Reserve(ctx context.Context, now time.Time, limit int, window time.Duration) (allowed bool, retryAt time.Time,
+		err error)
+}
+
+// InMemoryAuthAttemptStore implements AuthAttemptStore to record authentication attempts in memory, limiting only
+// the attempts made by the current process.
+type InMemoryAuthAttemptStore struct {
+	log slidingWindowLog
+	mu  sync.Mutex
+}
+
+// Reserve records an attempt at now if the budget allows it.
+func (s *InMemoryAuthAttemptStore) Reserve(_ context.Context, now time.Time, limit int, window time.Duration) (bool,
+	time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	allowed, retryAt := s.log.reserve(now, limit, window)
+	return allowed, retryAt, nil
+}
+
+// slidingWindowLog tracks the times of the events which occurred within a sliding window.
+type slidingWindowLog struct {
+	events []time.Time
+}
+
+// reserve records an event at now if fewer than limit events occurred within the window ending at now. Otherwise,
+// it returns the time at which the oldest event leaves the window.
+func (l *slidingWindowLog) reserve(now time.Time, limit int, window time.Duration) (bool, time.Time) {
+	start := now.Add(-window)
+	for len(l.events) > 0 && !l.events[0].After(start) {
+		l.events = l.events[1:]
+	}
+
+	if len(l.events) >= limit {
+		return false, l.events[len(l.events)-limit].Add(window)
+	}
+
+	l.events = append(l.events, now)
+	return true, time.Time{}
+}
+
+// authLimiter enforces the authentication budget, either waiting for the budget to allow another attempt or
+// failing fast with an *AuthLimitError.
+type authLimiter struct {
+	store  AuthAttemptStore
+	limit  int
+	window time.Duration
+	wait   bool
+}
+
+func (l *authLimiter) acquire(ctx context.Context) error {
+	// A window which isn't positive would let every attempt through, silently disabling the protection against
+	// being locked out, so authentication is refused instead.
+	if l.window <= 0 {
+		return fmt.Errorf("invalid authentication rate limit window %s, it must be positive", l.window)
+	}
+
+	for {
+		allowed, retryAt, err := l.store.Reserve(ctx, time.Now(), l.limit, l.window)
+		if err != nil {
+			return fmt.Errorf("failed to reserve authentication attempt: %w", err)
+		}
+
+		if allowed {
+			return nil
+		}
+
+		if !l.wait {
+			return &AuthLimitError{RetryAt: retryAt}
+		}
+
+		timer := time.NewTimer(time.Until(retryAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
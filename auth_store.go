@@ -3,8 +3,58 @@ package czds
 import (
 	"context"
 	"sync"
+	"time"
 )
 
+// Token is a JWT along with the time it expires at.
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// valid reports whether the token is set and doesn't expire within the given skew. When the expiry time isn't
+// known, it is read from the exp claim of the JWT. The skew is capped at half the lifetime of the JWT, when its iat
+// claim is known, so a skew at least as long as the lifetime doesn't make every freshly fetched JWT due for a
+// refresh.
+func (t Token) valid(skew time.Duration) bool {
+	if t.Value == "" {
+		return false
+	}
+
+	info := newTokenInfo(t)
+	if lifetime := info.ExpiresAt.Sub(info.IssuedAt); !info.IssuedAt.IsZero() && lifetime > 0 {
+		skew = min(skew, lifetime/2)
+	}
+
+	return time.Now().UTC().Add(skew).Before(info.ExpiresAt.UTC())
+}
+
+// AdaptTokenStore adapts a TokenStore to the TokenStoreV2 interface. The expiry of stored tokens is read from
+// their exp claim, and deleting a token saves an empty one.
+func AdaptTokenStore(store TokenStore) TokenStoreV2 {
+	return &tokenStoreAdapter{store: store}
+}
+
+type tokenStoreAdapter struct {
+	store TokenStore
+}
+
+func (a *tokenStoreAdapter) Get(ctx context.Context) (Token, error) {
+	value := a.store.Get(ctx)
+	if value == "" {
+		return Token{}, nil
+	}
+	return Token{Value: value, ExpiresAt: tokenExpiry(value)}, nil
+}
+
+func (a *tokenStoreAdapter) Save(ctx context.Context, token Token) error {
+	return a.store.Save(ctx, token.Value)
+}
+
+func (a *tokenStoreAdapter) Delete(ctx context.Context) error {
+	return a.store.Save(ctx, "")
+}
+
 // InMemoryTokenStore implements TokenStore to provide an in-memory storage mechanism for JWT tokens.
 type InMemoryTokenStore struct {
 	jwt string
@@ -0,0 +1,707 @@
+package czds_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestAuthTransport_ReauthenticatesOnHTTP401(t *testing.T) {
+	for name, tc := range map[string]struct {
+		unauthorisedResponses int32
+		expectedAuthCalls     int32
+		errAssert             assert.ErrorAssertionFunc
+	}{
+		"Success_ReplayedWithNewToken": {
+			unauthorisedResponses: 1,
+			expectedAuthCalls:     1,
+			errAssert:             assert.NoError,
+		},
+		"Fail_TokenStillRejected": {
+			unauthorisedResponses: 2,
+			expectedAuthCalls:     1,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var apiErr *czds.APIError
+				return assert.ErrorIs(t, err, czds.ErrTokenRejected) &&
+					assert.ErrorAs(t, err, &apiErr) &&
+					assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode) &&
+					assert.Equal(t, "token revoked", apiErr.Body)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls.Add(1)
+				testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+				_, err := w.Write([]byte(testResponse))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			var czdsCalls atomic.Int32
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "Bearer "+testGoodToken, r.Header.Get("Authorization"))
+
+				var reqBody struct {
+					Status string `json:"status"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+				require.Equal(t, "Approved", reqBody.Status)
+
+				if czdsCalls.Add(1) <= tc.unauthorisedResponses {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, err := w.Write([]byte("token revoked"))
+					require.NoError(t, err)
+					return
+				}
+
+				_, err := w.Write([]byte(`{"requests":[],"totalRequests":0}`))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			tokenStore := &czds.InMemoryTokenStore{}
+			require.NoError(t, tokenStore.Save(context.Background(), testGoodToken))
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.TokenStoreOpt(tokenStore),
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			_, err := client.ListAccessRequests(context.Background(),
+				czds.AccessRequestFilter{Status: czds.TLDStatusApproved})
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedAuthCalls, authCalls.Load())
+			assert.Equal(t, int32(2), czdsCalls.Load())
+		})
+	}
+}
+
+func TestAuthTransport_ConcurrentHTTP401sReauthenticateOnce(t *testing.T) {
+	revokedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls.Add(1)
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+	defer mockAccountsAPI.Close()
+
+	var rejections atomic.Int32
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+revokedToken {
+			// Reject the revoked token at slightly different times, so some requests only get their HTTP 401
+			// once others have already stored a new token.
+			time.Sleep(time.Duration(rejections.Add(1)) * 10 * time.Millisecond)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		require.Equal(t, "Bearer "+testGoodToken, r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(testTLDsResponse))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	tokenStore := &czds.InMemoryTokenStore{}
+	require.NoError(t, tokenStore.Save(context.Background(), revokedToken))
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.TokenStoreOpt(tokenStore),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := client.ListTLDs(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), authCalls.Load())
+}
+
+func TestAuthTransport_CoalescesConcurrentTokenFetches(t *testing.T) {
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer "+testGoodToken, r.Header.Get("Authorization"))
+
+		_, err := w.Write([]byte("test-1.com.\t10800\tin\tns\ttest-dns-1.com."))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := client.GetZoneFile(context.Background(), "com")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), authCalls.Load())
+}
+
+func TestAuthTransport_TokenRefreshSkew(t *testing.T) {
+	soonToExpireToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(2 * time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		opts              []czds.ClientOption
+		expectedToken     string
+		expectedAuthCalls int32
+	}{
+		"Success_StoredTokenUsedUntilExpiry": {
+			expectedToken:     soonToExpireToken,
+			expectedAuthCalls: 0,
+		},
+		"Success_StoredTokenRefreshedWithinSkew": {
+			opts:              []czds.ClientOption{czds.TokenRefreshSkew(5 * time.Minute)},
+			expectedToken:     testGoodToken,
+			expectedAuthCalls: 1,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls.Add(1)
+				testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+				_, err := w.Write([]byte(testResponse))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "Bearer "+tc.expectedToken, r.Header.Get("Authorization"))
+				_, err := w.Write([]byte(`[]`))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			tokenStore := &czds.InMemoryTokenStore{}
+			require.NoError(t, tokenStore.Save(context.Background(), soonToExpireToken))
+
+			client := czds.NewClient(testEmail, testPassword, append(tc.opts,
+				czds.TokenStoreOpt(tokenStore),
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))...)
+
+			_, err := client.ListTLDs(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAuthCalls, authCalls.Load())
+		})
+	}
+}
+
+func TestAuthTransport_TokenRefreshSkewLongerThanLifetime(t *testing.T) {
+	shortLivedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(2 * time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls.Add(1)
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, shortLivedToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer "+shortLivedToken, r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.TokenRefreshSkew(5*time.Minute),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ListTLDs(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), authCalls.Load())
+}
+
+func TestAuthTransport_BackgroundTokenRefresh(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	tokenStore := &czds.InMemoryTokenStore{}
+	client := czds.NewClient(testEmail, testPassword,
+		czds.TokenStoreOpt(tokenStore),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.BackgroundTokenRefresh(10*time.Millisecond))
+	defer client.Close()
+
+	assert.Eventually(t, func() bool {
+		return tokenStore.Get(context.Background()) == testGoodToken
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAuthTransport_BackgroundTokenRefreshBeforeExpiry(t *testing.T) {
+	expiresAt := time.Now().Add(3 * time.Second)
+	soonToExpireToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": expiresAt.Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	tokenStore := &czds.InMemoryTokenStore{}
+	require.NoError(t, tokenStore.Save(context.Background(), soonToExpireToken))
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.TokenStoreOpt(tokenStore),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.BackgroundTokenRefresh(time.Second))
+	defer client.Close()
+
+	assert.Eventually(t, func() bool {
+		return tokenStore.Get(context.Background()) == testGoodToken
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.True(t, time.Now().Before(time.Unix(expiresAt.Unix(), 0)), "JWT should be replaced before it expires")
+}
+
+type testTokenStoreV2 struct {
+	mu      sync.Mutex
+	token   czds.Token
+	getErr  error
+	deletes int
+}
+
+func (s *testTokenStoreV2) Get(_ context.Context) (czds.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, s.getErr
+}
+
+func (s *testTokenStoreV2) Save(_ context.Context, token czds.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
+
+func (s *testTokenStoreV2) Delete(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = czds.Token{}
+	s.deletes++
+	return nil
+}
+
+func TestAuthTransport_TokenStoreV2(t *testing.T) {
+	errBackend := errors.New("backend unavailable")
+
+	for name, tc := range map[string]struct {
+		store             *testTokenStoreV2
+		unauthorised      bool
+		expectedAuthCalls int32
+		expectedDeletes   int
+		errAssert         assert.ErrorAssertionFunc
+	}{
+		"Success_StoredTokenUsed": {
+			store:     &testTokenStoreV2{token: czds.Token{Value: "stored-token", ExpiresAt: time.Now().Add(time.Hour)}},
+			errAssert: assert.NoError,
+		},
+		"Success_ExpiredTokenRefreshed": {
+			store:             &testTokenStoreV2{token: czds.Token{Value: testGoodToken, ExpiresAt: time.Now()}},
+			expectedAuthCalls: 1,
+			errAssert:         assert.NoError,
+		},
+		"Success_RejectedTokenDeleted": {
+			store:             &testTokenStoreV2{token: czds.Token{Value: "stored-token", ExpiresAt: time.Now().Add(time.Hour)}},
+			unauthorised:      true,
+			expectedAuthCalls: 1,
+			expectedDeletes:   1,
+			errAssert:         assert.NoError,
+		},
+		"Fail_StoreError": {
+			store: &testTokenStoreV2{getErr: errBackend},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, errBackend)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls.Add(1)
+				testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+				_, err := w.Write([]byte(testResponse))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.unauthorised && r.Header.Get("Authorization") != "Bearer "+testGoodToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_, err := w.Write([]byte(`[]`))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.TokenStoreOpt(&czds.InMemoryTokenStore{}),
+				czds.TokenStoreV2Opt(tc.store),
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			_, err := client.ListTLDs(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedAuthCalls, authCalls.Load())
+			assert.Equal(t, tc.expectedDeletes, tc.store.deletes)
+		})
+	}
+}
+
+func TestAuthTransport_TypedAuthenticationErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		statusCode         int
+		header             http.Header
+		body               string
+		expectedErr        error
+		expectedMessage    string
+		expectedRetryAfter time.Duration
+	}{
+		"InvalidCredentials": {
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"message":"Invalid username or password"}`,
+			expectedErr:     czds.ErrInvalidCredentials,
+			expectedMessage: "Invalid username or password",
+		},
+		"InvalidCredentials_LockoutWarning": {
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"message":"Invalid password, account will be locked after 3 attempts"}`,
+			expectedErr:     czds.ErrInvalidCredentials,
+			expectedMessage: "Invalid password, account will be locked after 3 attempts",
+		},
+		"AccountLocked": {
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"message":"Your account has been locked"}`,
+			expectedErr:     czds.ErrAccountLocked,
+			expectedMessage: "Your account has been locked",
+		},
+		"AccountDisabled": {
+			statusCode:      http.StatusForbidden,
+			body:            `{"message":"Account disabled"}`,
+			expectedErr:     czds.ErrAccountLocked,
+			expectedMessage: "Account disabled",
+		},
+		"Forbidden_UnknownMessage": {
+			statusCode:      http.StatusForbidden,
+			body:            `{"message":"Access denied"}`,
+			expectedErr:     czds.ErrAccountLocked,
+			expectedMessage: "Access denied",
+		},
+		"BadRequest": {
+			statusCode:      http.StatusBadRequest,
+			body:            `{"message":"Username is required"}`,
+			expectedErr:     czds.ErrInvalidCredentials,
+			expectedMessage: "Username is required",
+		},
+		"RateLimited": {
+			statusCode:         http.StatusTooManyRequests,
+			header:             http.Header{"Retry-After": []string{"300"}},
+			body:               "Too many requests",
+			expectedErr:        czds.ErrAuthRateLimited,
+			expectedMessage:    "Too many requests",
+			expectedRetryAfter: 5 * time.Minute,
+		},
+		"ServerFailure": {
+			statusCode:  http.StatusBadGateway,
+			expectedErr: czds.ErrAuthServerFailure,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tc.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(tc.statusCode)
+				_, err := w.Write([]byte(tc.body))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword, czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL))
+
+			_, err := client.ListTLDs(context.Background())
+			require.ErrorIs(t, err, tc.expectedErr)
+
+			var authErr *czds.AuthError
+			require.ErrorAs(t, err, &authErr)
+			assert.Equal(t, tc.statusCode, authErr.StatusCode)
+			assert.Equal(t, tc.expectedMessage, authErr.Message)
+			assert.Equal(t, tc.expectedRetryAfter, authErr.RetryAfter)
+		})
+	}
+}
+
+func TestAuthTransport_AuthRateLimit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts              []czds.ClientOption
+		expectedAuthCalls int32
+		errAssert         assert.ErrorAssertionFunc
+	}{
+		"Fail_FailsFastOnceBudgetExhausted": {
+			opts:              []czds.ClientOption{czds.AuthRateLimit(1, time.Hour)},
+			expectedAuthCalls: 1,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var limitErr *czds.AuthLimitError
+				return assert.ErrorIs(t, err, czds.ErrAuthLimitExceeded) &&
+					assert.ErrorAs(t, err, &limitErr) &&
+					assert.WithinDuration(t, time.Now().Add(time.Hour), limitErr.RetryAt, time.Minute)
+			},
+		},
+		"Fail_WaitsForBudget": {
+			opts: []czds.ClientOption{
+				czds.AuthRateLimit(1, 50*time.Millisecond),
+				czds.AuthRateLimitWait(),
+			},
+			expectedAuthCalls: 2,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrAuthServerFailure)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls.Add(1)
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer mockAccountsAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				append(tc.opts, czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL))...)
+
+			_, err := client.ListTLDs(context.Background())
+			require.ErrorIs(t, err, czds.ErrAuthServerFailure)
+
+			_, err = client.ListTLDs(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedAuthCalls, authCalls.Load())
+		})
+	}
+}
+
+func TestAuthTransport_AuthRateLimitInvalidWindow(t *testing.T) {
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls.Add(1)
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+	defer mockAccountsAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.AuthRateLimit(1, 0))
+
+	_, err := client.ListTLDs(context.Background())
+	assert.ErrorContains(t, err, "invalid authentication rate limit window")
+	assert.Equal(t, int32(0), authCalls.Load())
+}
+
+func TestAuthTransport_ContextAwareAuthentication(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts      []czds.ClientOption
+		ctx       func() (context.Context, context.CancelFunc)
+		errAssert assert.ErrorAssertionFunc
+	}{
+		"Fail_CallerContextCancelled": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		"Fail_AuthTimeout": {
+			opts: []czds.ClientOption{czds.AuthTimeout(50 * time.Millisecond)},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			authCancelled := make(chan struct{})
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := io.Copy(io.Discard, r.Body)
+				require.NoError(t, err)
+
+				<-r.Context().Done()
+				close(authCancelled)
+			}))
+			defer mockAccountsAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				append(tc.opts, czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL))...)
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, err := client.ListTLDs(ctx)
+			tc.errAssert(t, err)
+			assert.Less(t, time.Since(start), time.Second)
+
+			select {
+			case <-authCancelled:
+			case <-time.After(time.Second):
+				t.Error("authentication request was not cancelled")
+			}
+		})
+	}
+}
+
+func TestAuthTransport_CancelledFetchNotShared(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	// The first authentication request takes a while to wind down once cancelled.
+	var authRequests atomic.Int32
+	authHTTPClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if authRequests.Add(1) == 1 {
+				<-req.Context().Done()
+				time.Sleep(200 * time.Millisecond)
+				return nil, req.Context().Err()
+			}
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.AuthHTTPClient(authHTTPClient),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ListTLDs(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = client.ListTLDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), authRequests.Load())
+}
+
+func TestAuthTransport_AuthHTTPClient(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	var authRequests atomic.Int32
+	authHTTPClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authRequests.Add(1)
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.AuthHTTPClient(authHTTPClient),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	_, err := client.ListTLDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), authRequests.Load())
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
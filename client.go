@@ -9,20 +9,26 @@
 package czds
 
 import (
-	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
+	"time"
 )
 
+const defaultAuthTimeout = 30 * time.Second
+
 // Client represents a client for interacting with the ICANN Centralized Zone Data Service (CZDS).
 type Client struct {
 	httpClient     *http.Client
 	czdsAPIBaseURL string
+	auth           *authTransport
+	stop           chan struct{}
+	closeOnce      sync.Once
 }
 
 // NewClient initialises and returns a new Client instance for interacting with the ICANN
@@ -30,20 +36,16 @@ type Client struct {
 // It accepts email and password for authentication, enabling the client to obtain domain information
 // from TLD zone files, list TLDs to allow to check their approval status for the account.
 // If no TokenStore is provided, a new in-memory token store will be used by default, allowing for
-// automatic management of JWT tokens.
+// automatic management of JWT tokens. When a CredentialsProvider is provided, it is consulted every time the
+// client authenticates instead of the given email and password, which may then be left empty.
 func NewClient(email, password string, opts ...ClientOption) *Client {
-	options := &Options{}
+	options := &Options{
+		authTimeout: defaultAuthTimeout,
+	}
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	var tokenStore TokenStore
-	if options.tokenStore != nil {
-		tokenStore = options.tokenStore
-	} else {
-		tokenStore = &InMemoryTokenStore{}
-	}
-
 	accountsAPIBaseURL := "https://account-api.icann.org/api"
 	if options.accountsAPIBaseURL != "" {
 		accountsAPIBaseURL = options.accountsAPIBaseURL
@@ -54,29 +56,59 @@ func NewClient(email, password string, opts ...ClientOption) *Client {
 		czdsAPIBaseURL = options.czdsAPIBaseURL
 	}
 
-	httpClient := &http.Client{
-		Transport: &authTransport{
-			httpClient:         http.DefaultClient,
-			email:              email,
-			password:           password,
-			tokenStore:         tokenStore,
-			accountsAPIBaseURL: accountsAPIBaseURL,
-		},
+	credentials := options.credentialsProvider
+	if credentials == nil {
+		credentials = StaticCredentials(email, password)
+	}
+
+	httpClient, base, authHTTPClient := options.getHTTPClients()
+
+	auth := &authTransport{
+		base:               options.withDownloadQuota(base),
+		httpClient:         authHTTPClient,
+		credentials:        credentials,
+		tokenStore:         options.getTokenStore(),
+		locker:             options.tokenLocker,
+		accountsAPIBaseURL: accountsAPIBaseURL,
+		refreshSkew:        options.tokenRefreshSkew,
+		limiter:            options.getAuthLimiter(),
+		authTimeout:        options.authTimeout,
 	}
 
-	return &Client{
+	httpClient.Transport = options.withRetries(options.withRequestRateLimit(auth))
+
+	client := &Client{
 		httpClient:     httpClient,
 		czdsAPIBaseURL: czdsAPIBaseURL,
+		auth:           auth,
+		stop:           make(chan struct{}),
+	}
+
+	if options.backgroundRefreshEvery > 0 {
+		go auth.refreshInBackground(options.backgroundRefreshEvery, client.stop)
 	}
+
+	return client
+}
+
+// Close stops any background work started by the client, such as the background JWT refresh. Requests can still be
+// made once the client is closed, and Close can be called multiple times.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+	return nil
 }
 
 // GetZoneFile fetches and parses a zone file for a given TLD from the ICANN CZDS API.
 // It requires a context for operation cancellation, a JWT for authorization, and the TLD name.
-// The function returns a map of domain names to their records.
+// The function returns a map of domain names to their records. Parse options can be provided to keep only
+// the records of interest, which are applied before any record is allocated.
 // An error is returned if the operation fails at any stage, including request creation, HTTP
 // communication, decompression, or file parsing. It handles gzip-compressed zone files and expects
-// authorized access to the requested zone file.
-func (c *Client) GetZoneFile(ctx context.Context, tld string) (map[string][]string, error) {
+// authorized access to the requested zone file. When a download quota is configured, a *QuotaExceededError is
+// returned without sending the request once the quota of the TLD is exhausted.
+func (c *Client) GetZoneFile(ctx context.Context, tld string, opts ...ParseOption) (map[string][]string, error) {
 	endpoint := fmt.Sprintf(c.czdsAPIBaseURL+"/downloads/%s.zone", tld)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
 	if err != nil {
@@ -90,7 +122,7 @@ func (c *Client) GetZoneFile(ctx context.Context, tld string) (map[string][]stri
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("expected HTTP 200 response, got %d", resp.StatusCode)
+		return nil, newAPIError(req, resp)
 	}
 
 	var reader io.Reader = resp.Body
@@ -103,23 +135,7 @@ func (c *Client) GetZoneFile(ctx context.Context, tld string) (map[string][]stri
 		reader = gzReader
 	}
 
-	domainMap := make(map[string][]string)
-	scanner := bufio.NewScanner(reader)
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, "\t")
-		if len(parts) > 1 {
-			domain := parts[0]
-			record := strings.Join(parts[1:], ",")
-			domainMap[domain] = append(domainMap[domain], record)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan zone file: %w", err)
-	}
-
-	return domainMap, nil
+	return ParseZone(reader, opts...)
 }
 
 func (c *Client) ListTLDs(ctx context.Context) ([]TLD, error) {
@@ -134,9 +150,10 @@ func (c *Client) ListTLDs(ctx context.Context) ([]TLD, error) {
 	if err != nil {
 		return nil, fmt.Errorf("list TLDs request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("expected HTTP 200 response, got %d", resp.StatusCode)
+		return nil, newAPIError(req, resp)
 	}
 
 	var tlds []TLD
@@ -146,3 +163,78 @@ func (c *Client) ListTLDs(ctx context.Context) ([]TLD, error) {
 
 	return tlds, nil
 }
+
+// doJSON sends a request to the given CZDS API path, encoding in as the JSON request body when it isn't nil and
+// decoding the JSON response body into out when it isn't nil.
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader = http.NoBody
+	if in != nil {
+		buf := new(bytes.Buffer)
+		if err := json.NewEncoder(buf).Encode(in); err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		body = buf
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.czdsAPIBaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(req, resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// requestIDHeaders are the response headers checked, in order, for the ID the server assigned to a request.
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "X-Correlation-Id"}
+
+// newAPIError describes the unsuccessful response to the given request, reading and closing its body.
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Body:       readErrorMessage(resp),
+	}
+
+	for _, header := range requestIDHeaders {
+		if apiErr.RequestID = resp.Header.Get(header); apiErr.RequestID != "" {
+			break
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		apiErr.Err = ErrUnauthorized
+	case resp.StatusCode == http.StatusForbidden:
+		apiErr.Err = ErrForbidden
+	case resp.StatusCode == http.StatusNotFound:
+		apiErr.Err = ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		apiErr.Err = ErrServerFailure
+	}
+
+	return apiErr
+}
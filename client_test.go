@@ -5,10 +5,14 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -425,3 +429,178 @@ func TestListTLDs(t *testing.T) {
 		})
 	}
 }
+
+func newTestAccountsAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/authenticate", r.URL.Path)
+
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+}
+
+func TestHTTPClientOptions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts                   func(transport http.RoundTripper) []czds.ClientOption
+		expectedTransportCalls int32
+		errAssert              assert.ErrorAssertionFunc
+	}{
+		"Success_HTTPTransport": {
+			opts: func(transport http.RoundTripper) []czds.ClientOption {
+				return []czds.ClientOption{czds.HTTPTransport(transport)}
+			},
+			expectedTransportCalls: 3,
+			errAssert:              assert.NoError,
+		},
+		"Success_HTTPClient": {
+			opts: func(transport http.RoundTripper) []czds.ClientOption {
+				return []czds.ClientOption{czds.HTTPClient(&http.Client{Transport: transport})}
+			},
+			expectedTransportCalls: 3,
+			errAssert:              assert.NoError,
+		},
+		"Fail_HTTPClientRedirectPolicy": {
+			opts: func(transport http.RoundTripper) []czds.ClientOption {
+				return []czds.ClientOption{czds.HTTPClient(&http.Client{
+					Transport: transport,
+					CheckRedirect: func(req *http.Request, via []*http.Request) error {
+						return http.ErrUseLastResponse
+					},
+				})}
+			},
+			expectedTransportCalls: 2,
+			errAssert:              assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /tlds", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/tlds/all", http.StatusFound)
+			})
+			mux.HandleFunc("GET /tlds/all", func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(`[]`))
+				require.NoError(t, err)
+			})
+			mockCZDSAPI := httptest.NewServer(mux)
+			defer mockCZDSAPI.Close()
+
+			var transportCalls atomic.Int32
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				transportCalls.Add(1)
+				return http.DefaultTransport.RoundTrip(req)
+			})
+
+			client := czds.NewClient(testEmail, testPassword, append(tc.opts(transport),
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))...)
+
+			_, err := client.ListTLDs(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedTransportCalls, transportCalls.Load())
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		statusCode         int
+		retryAfter         string
+		expectedErr        error
+		expectedRetryAfter time.Duration
+	}{
+		"Forbidden": {
+			statusCode:  http.StatusForbidden,
+			expectedErr: czds.ErrForbidden,
+		},
+		"NotFound": {
+			statusCode:  http.StatusNotFound,
+			expectedErr: czds.ErrNotFound,
+		},
+		"RateLimited": {
+			statusCode:         http.StatusTooManyRequests,
+			retryAfter:         "120",
+			expectedErr:        czds.ErrRateLimited,
+			expectedRetryAfter: 2 * time.Minute,
+		},
+		"ServerFailure": {
+			statusCode:  http.StatusServiceUnavailable,
+			expectedErr: czds.ErrServerFailure,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			body := strings.Repeat("x", 2048)
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "test-request-id")
+				w.Header().Set("Retry-After", tc.retryAfter)
+				w.WriteHeader(tc.statusCode)
+				_, err := w.Write([]byte(body))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			_, err := client.GetZoneFile(context.Background(), "com")
+			require.ErrorIs(t, err, tc.expectedErr)
+
+			var apiErr *czds.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, &czds.APIError{
+				StatusCode: tc.statusCode,
+				Method:     http.MethodGet,
+				URL:        mockCZDSAPI.URL + "/downloads/com.zone",
+				RequestID:  "test-request-id",
+				Body:       body[:1024],
+				RetryAfter: tc.expectedRetryAfter,
+				Err:        tc.expectedErr,
+			}, apiErr)
+		})
+	}
+}
+
+func TestAPIError_Unauthorized(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, err := w.Write([]byte("token revoked"))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	_, err := client.ListTLDs(context.Background())
+	assert.ErrorIs(t, err, czds.ErrUnauthorized)
+	assert.ErrorIs(t, err, czds.ErrTokenRejected)
+
+	var apiErr *czds.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, mockCZDSAPI.URL+"/tlds", apiErr.URL)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.Equal(t, "token revoked", apiErr.Body)
+}
@@ -0,0 +1,114 @@
+package czds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// DefaultEmailEnvVar is the environment variable EnvCredentials reads the account email from by default.
+	DefaultEmailEnvVar = "CZDS_EMAIL"
+	// DefaultPasswordEnvVar is the environment variable EnvCredentials reads the account password from by default.
+	DefaultPasswordEnvVar = "CZDS_PASSWORD"
+)
+
+// ErrNoCredentials is returned by a CredentialsProvider which has no credentials to provide.
+var ErrNoCredentials = errors.New("no credentials found")
+
+// Credentials holds the ICANN account email and password used to authenticate.
+type Credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// CredentialsProvider provides the credentials used to authenticate with the ICANN accounts API. It is consulted
+// every time the client authenticates, so rotated credentials are picked up without recreating the client.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc is an adapter allowing ordinary functions to be used as a CredentialsProvider.
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f(ctx).
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a CredentialsProvider always providing the given email and password.
+func StaticCredentials(email, password string) CredentialsProvider {
+	return CredentialsProviderFunc(func(_ context.Context) (Credentials, error) {
+		return Credentials{Email: email, Password: password}, nil
+	})
+}
+
+// EnvCredentials returns a CredentialsProvider reading the email and password from the given environment
+// variables, defaulting to DefaultEmailEnvVar and DefaultPasswordEnvVar when empty. ErrNoCredentials is returned
+// if either variable isn't set.
+func EnvCredentials(emailVar, passwordVar string) CredentialsProvider {
+	if emailVar == "" {
+		emailVar = DefaultEmailEnvVar
+	}
+	if passwordVar == "" {
+		passwordVar = DefaultPasswordEnvVar
+	}
+
+	return CredentialsProviderFunc(func(_ context.Context) (Credentials, error) {
+		email, password := os.Getenv(emailVar), os.Getenv(passwordVar)
+		if email == "" || password == "" {
+			return Credentials{}, fmt.Errorf("%w in %s and %s environment variables", ErrNoCredentials, emailVar,
+				passwordVar)
+		}
+		return Credentials{Email: email, Password: password}, nil
+	})
+}
+
+// FileCredentials returns a CredentialsProvider reading the email and password from a JSON file of the form
+// {"email": "...", "password": "..."}. The file is read every time the credentials are needed. ErrNoCredentials
+// is returned if the file doesn't exist.
+func FileCredentials(path string) CredentialsProvider {
+	return CredentialsProviderFunc(func(_ context.Context) (Credentials, error) {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return Credentials{}, fmt.Errorf("%w in %s", ErrNoCredentials, path)
+		}
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+
+		var creds Credentials
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return Credentials{}, fmt.Errorf("failed to decode credentials file: %w", err)
+		}
+
+		if creds.Email == "" || creds.Password == "" {
+			return Credentials{}, fmt.Errorf("%w in %s", ErrNoCredentials, path)
+		}
+
+		return creds, nil
+	})
+}
+
+// ChainCredentials returns a CredentialsProvider trying each of the given providers in turn, returning the
+// credentials of the first one which succeeds. Only providers failing with ErrNoCredentials are skipped, any other
+// error, such as a credentials file which can't be read or decoded, is returned straight away rather than falling
+// back to possibly stale credentials. If no provider has credentials, their errors are returned joined.
+func ChainCredentials(providers ...CredentialsProvider) CredentialsProvider {
+	return CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		errs := []error{ErrNoCredentials}
+		for _, provider := range providers {
+			creds, err := provider.Credentials(ctx)
+			if err == nil {
+				return creds, nil
+			}
+			if !errors.Is(err, ErrNoCredentials) {
+				return Credentials{}, err
+			}
+			errs = append(errs, err)
+		}
+		return Credentials{}, errors.Join(errs...)
+	})
+}
@@ -0,0 +1,117 @@
+package czds_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	_, err := czds.EnvCredentials("", "").Credentials(context.Background())
+	assert.ErrorIs(t, err, czds.ErrNoCredentials)
+
+	t.Setenv(czds.DefaultEmailEnvVar, testEmail)
+	t.Setenv(czds.DefaultPasswordEnvVar, testPassword)
+
+	creds, err := czds.EnvCredentials("", "").Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, czds.Credentials{Email: testEmail, Password: testPassword}, creds)
+}
+
+func TestFileCredentials(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content             string
+		expectedCredentials czds.Credentials
+		errAssert           assert.ErrorAssertionFunc
+	}{
+		"Success": {
+			content:             `{"email":"test-email","password":"test-password"}`,
+			expectedCredentials: czds.Credentials{Email: testEmail, Password: testPassword},
+			errAssert:           assert.NoError,
+		},
+		"Fail_MissingFile": {
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrNoCredentials)
+			},
+		},
+		"Fail_CorruptFile": {
+			content:   `{"email":`,
+			errAssert: assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "credentials.json")
+			if tc.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+			}
+
+			creds, err := czds.FileCredentials(path).Credentials(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedCredentials, creds)
+		})
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	missing := czds.FileCredentials(filepath.Join(t.TempDir(), "missing.json"))
+
+	creds, err := czds.ChainCredentials(missing, czds.StaticCredentials(testEmail, testPassword)).
+		Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, czds.Credentials{Email: testEmail, Password: testPassword}, creds)
+
+	_, err = czds.ChainCredentials(missing).Credentials(context.Background())
+	assert.ErrorIs(t, err, czds.ErrNoCredentials)
+
+	malformedPath := filepath.Join(t.TempDir(), "malformed.json")
+	require.NoError(t, os.WriteFile(malformedPath, []byte(`{"email":`), 0o600))
+	t.Setenv(czds.DefaultEmailEnvVar, testEmail)
+	t.Setenv(czds.DefaultPasswordEnvVar, "stale-password")
+
+	creds, err = czds.ChainCredentials(czds.FileCredentials(malformedPath), czds.EnvCredentials("", "")).
+		Credentials(context.Background())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, czds.ErrNoCredentials)
+	assert.Equal(t, czds.Credentials{}, creds)
+}
+
+func TestCredentialsProvider_RotatedCredentialsPickedUp(t *testing.T) {
+	var authenticatedAs []string
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		authenticatedAs = append(authenticatedAs, reqBody.Username+":"+reqBody.Password)
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockAccountsAPI.Close()
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	client := czds.NewClient("", "",
+		czds.CredentialsProviderOpt(czds.FileCredentials(path)),
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL))
+
+	for _, password := range []string{"password-1", "password-2"} {
+		require.NoError(t, os.WriteFile(path, []byte(`{"email":"test-email","password":"`+password+`"}`), 0o600))
+
+		_, err := client.ListTLDs(context.Background())
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, []string{"test-email:password-1", "test-email:password-2"}, authenticatedAs)
+}
@@ -0,0 +1,195 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DomainSet is a compact, immutable set of domain names, kept as a sorted slice of lower-cased names without the
+// trailing dot.
+type DomainSet struct {
+	names []string
+}
+
+// NewDomainSet returns a DomainSet holding the given names. Names are lower-cased and stripped of the trailing dot.
+func NewDomainSet(names ...string) DomainSet {
+	normalised := make([]string, 0, len(names))
+	for _, name := range names {
+		normalised = append(normalised, normaliseDomain(name))
+	}
+	slices.Sort(normalised)
+	return DomainSet{names: slices.Compact(normalised)}
+}
+
+// Len returns the number of names in the set.
+func (s DomainSet) Len() int {
+	return len(s.names)
+}
+
+// Contains reports whether the set holds the given name.
+func (s DomainSet) Contains(name string) bool {
+	_, found := slices.BinarySearch(s.names, normaliseDomain(name))
+	return found
+}
+
+// Names returns the names in the set in lexical order.
+func (s DomainSet) Names() []string {
+	return slices.Clone(s.names)
+}
+
+// Union returns a set holding the names found in either set.
+func (s DomainSet) Union(other DomainSet) DomainSet {
+	return s.merge(other, true, true, true)
+}
+
+// Intersect returns a set holding the names found in both sets.
+func (s DomainSet) Intersect(other DomainSet) DomainSet {
+	return s.merge(other, false, true, false)
+}
+
+// Difference returns a set holding the names found in s but not in other.
+func (s DomainSet) Difference(other DomainSet) DomainSet {
+	return s.merge(other, true, false, false)
+}
+
+// merge walks both sorted sets at once, keeping the names found only in s, in both sets, or only in other.
+func (s DomainSet) merge(other DomainSet, onlyS, both, onlyOther bool) DomainSet {
+	var names []string
+	i, j := 0, 0
+	for i < len(s.names) || j < len(other.names) {
+		switch {
+		case j == len(other.names) || (i < len(s.names) && s.names[i] < other.names[j]):
+			if onlyS {
+				names = append(names, s.names[i])
+			}
+			i++
+		case i == len(s.names) || other.names[j] < s.names[i]:
+			if onlyOther {
+				names = append(names, other.names[j])
+			}
+			j++
+		default:
+			if both {
+				names = append(names, s.names[i])
+			}
+			i++
+			j++
+		}
+	}
+	return DomainSet{names: names}
+}
+
+// ZoneNames classifies the owner names found in a TLD zone file.
+type ZoneNames struct {
+	// Apex is the name of the TLD itself.
+	Apex string
+	// Domains holds the unique delegated second-level names, derived from every delegation point.
+	Domains DomainSet
+	// Delegations holds the owner names below the apex carrying NS records.
+	Delegations DomainSet
+	// Glue holds the owner names at or below a delegation point carrying address records, typically the
+	// nameserver hostnames of the delegated domains.
+	Glue DomainSet
+}
+
+// ClassifyZone classifies the owner names of the domain map returned by GetZoneFile for the given TLD into the
+// apex, delegation points, glue hosts and the delegated second-level names.
+func ClassifyZone(tld string, zone map[string][]string) ZoneNames {
+	apex := normaliseDomain(tld)
+
+	var delegations, hosts []string
+	for owner, records := range zone {
+		name := normaliseDomain(owner)
+		if name == apex {
+			continue
+		}
+
+		for _, rec := range records {
+			switch recordType(rec) {
+			case "ns":
+				delegations = append(delegations, name)
+			case "a", "aaaa":
+				hosts = append(hosts, name)
+			}
+		}
+	}
+
+	delegationSet := NewDomainSet(delegations...)
+
+	var glue, domains []string
+	for _, host := range hosts {
+		if isAtOrBelowDelegation(host, apex, delegationSet) {
+			glue = append(glue, host)
+		}
+	}
+	for _, name := range delegationSet.names {
+		if domain, ok := RegistrableDomain(apex, name); ok {
+			domains = append(domains, domain)
+		}
+	}
+
+	return ZoneNames{
+		Apex:        apex,
+		Domains:     NewDomainSet(domains...),
+		Delegations: delegationSet,
+		Glue:        NewDomainSet(glue...),
+	}
+}
+
+// RegistrableDomain returns the second-level name under the given TLD to which name belongs, e.g. "example.com"
+// for "ns1.example.com." under "com". It reports false if name is the TLD itself or does not belong to it.
+func RegistrableDomain(tld, name string) (string, bool) {
+	tld, name = normaliseDomain(tld), normaliseDomain(name)
+
+	prefix, found := strings.CutSuffix(name, "."+tld)
+	if !found || prefix == "" {
+		return "", false
+	}
+
+	if idx := strings.LastIndexByte(prefix, '.'); idx >= 0 {
+		prefix = prefix[idx+1:]
+	}
+
+	return prefix + "." + tld, true
+}
+
+// GetDomains fetches the zone file for the given TLD and returns the unique set of delegated second-level names.
+// Only the NS records are retained while parsing, so this is considerably cheaper than GetZoneFile.
+func (c *Client) GetDomains(ctx context.Context, tld string) (DomainSet, error) {
+	zone, err := c.GetZoneFile(ctx, tld, RecordTypes("ns"))
+	if err != nil {
+		return DomainSet{}, fmt.Errorf("failed to get %s zone file: %w", tld, err)
+	}
+
+	return ClassifyZone(tld, zone).Domains, nil
+}
+
+func isAtOrBelowDelegation(name, apex string, delegations DomainSet) bool {
+	for name != apex && name != "" {
+		if delegations.Contains(name) {
+			return true
+		}
+
+		_, parent, found := strings.Cut(name, ".")
+		if !found {
+			return false
+		}
+		name = parent
+	}
+	return false
+}
+
+// recordType returns the lower-cased type of a record as held in the domain map returned by GetZoneFile.
+func recordType(rec string) string {
+	parts := strings.SplitN(rec, ",", 4)
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.ToLower(parts[2])
+}
+
+func normaliseDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
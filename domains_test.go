@@ -0,0 +1,87 @@
+package czds_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestDomainSet(t *testing.T) {
+	a := czds.NewDomainSet("Example.com.", "test-1.com", "test-2.com", "example.com")
+	b := czds.NewDomainSet("test-2.com", "test-3.com")
+
+	assert.Equal(t, 3, a.Len())
+	assert.True(t, a.Contains("EXAMPLE.COM."))
+	assert.False(t, a.Contains("test-3.com"))
+	assert.Equal(t, []string{"example.com", "test-1.com", "test-2.com", "test-3.com"}, a.Union(b).Names())
+	assert.Equal(t, []string{"test-2.com"}, a.Intersect(b).Names())
+	assert.Equal(t, []string{"example.com", "test-1.com"}, a.Difference(b).Names())
+	assert.Equal(t, []string{"test-3.com"}, b.Difference(a).Names())
+}
+
+func TestClassifyZone(t *testing.T) {
+	zone, err := czds.ParseZone(strings.NewReader(testZoneFile + `
+deep.sub.test-3.com.	10800	in	ns	test-dns-3.com.
+ns1.test-1.com.	10800	in	aaaa	2001:db8::1
+ns.other.net.	10800	in	a	192.0.2.2`))
+	require.NoError(t, err)
+
+	names := czds.ClassifyZone("com", zone)
+	assert.Equal(t, "com", names.Apex)
+	assert.Equal(t, []string{"test-1.com", "test-2.com", "test-3.com"}, names.Domains.Names())
+	assert.Equal(t, []string{"deep.sub.test-3.com", "test-1.com", "test-2.com"}, names.Delegations.Names())
+	assert.Equal(t, []string{"ns1.test-1.com"}, names.Glue.Names())
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tld            string
+		name           string
+		expectedDomain string
+		expectedOK     bool
+	}{
+		"Success_SecondLevel": {tld: "com", name: "example.com.", expectedDomain: "example.com", expectedOK: true},
+		"Success_DeeperLabel": {tld: "com.", name: "ns1.Example.com", expectedDomain: "example.com", expectedOK: true},
+		"Fail_Apex":           {tld: "com", name: "com."},
+		"Fail_OtherTLD":       {tld: "com", name: "example.net."},
+		"Fail_SuffixOnly":     {tld: "com", name: "examplecom."},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			domain, ok := czds.RegistrableDomain(tc.tld, tc.name)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedDomain, domain)
+		})
+	}
+}
+
+func TestGetDomains(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/downloads/com.zone", r.URL.Path)
+
+		_, err := w.Write([]byte(testZoneFile))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	domains, err := client.GetDomains(context.Background(), "com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-1.com", "test-2.com"}, domains.Names())
+}
@@ -0,0 +1,135 @@
+package czds
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// passphraseKeyIterations is the number of PBKDF2-HMAC-SHA256 iterations used to derive a key from a
+	// passphrase, as recommended by OWASP.
+	passphraseKeyIterations = 600_000
+	passphraseKeySize       = 32
+	minPassphraseSaltSize   = 16
+)
+
+// EncryptedTokenStore is a TokenStore decorator encrypting the JWT with AES-GCM before delegating to the
+// underlying TokenStore, so bearer tokens are never stored in plaintext. Tokens which can't be decrypted, e.g.
+// because the ciphertext was tampered with or the key changed, are treated as no token being stored.
+type EncryptedTokenStore struct {
+	store TokenStore
+	aead  cipher.AEAD
+}
+
+// NewEncryptedTokenStore returns an EncryptedTokenStore encrypting tokens with the given AES key, which must be
+// 16, 24 or 32 bytes long, before delegating to store.
+func NewEncryptedTokenStore(store TokenStore, key []byte) (*EncryptedTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES-GCM cipher: %w", err)
+	}
+
+	return &EncryptedTokenStore{
+		store: store,
+		aead:  aead,
+	}, nil
+}
+
+// NewEncryptedTokenStoreFromPassphrase returns an EncryptedTokenStore encrypting tokens with an AES-256 key derived
+// from the given passphrase and salt using PBKDF2-HMAC-SHA256. The salt must be at least 16 bytes long and has to
+// stay the same for previously stored tokens to be decrypted.
+func NewEncryptedTokenStoreFromPassphrase(store TokenStore, passphrase string, salt []byte) (*EncryptedTokenStore,
+	error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	if len(salt) < minPassphraseSaltSize {
+		return nil, fmt.Errorf("salt must be at least %d bytes long", minPassphraseSaltSize)
+	}
+
+	key := pbkdf2SHA256([]byte(passphrase), salt, passphraseKeyIterations, passphraseKeySize)
+
+	return NewEncryptedTokenStore(store, key)
+}
+
+// Save encrypts the given JWT and saves it to the underlying store. An empty token is saved as is, so the token
+// can still be cleared.
+func (s *EncryptedTokenStore) Save(ctx context.Context, token string) error {
+	if token == "" {
+		return s.store.Save(ctx, "")
+	}
+
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(token)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, []byte(token), nil)
+
+	return s.store.Save(ctx, base64.RawURLEncoding.EncodeToString(sealed))
+}
+
+// Get retrieves the encrypted JWT from the underlying store and decrypts it, returning an empty string if it
+// can't be decrypted.
+func (s *EncryptedTokenStore) Get(ctx context.Context) string {
+	encoded := s.store.Get(ctx)
+	if encoded == "" {
+		return ""
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return ""
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	token, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return ""
+	}
+
+	return string(token)
+}
+
+// pbkdf2SHA256 derives a key of the given length from the password and salt as described in RFC 8018.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + prf.Size() - 1) / prf.Size()
+
+	key := make([]byte, 0, blocks*prf.Size())
+	u := make([]byte, 0, prf.Size())
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, uint32(block)))
+		u = prf.Sum(u[:0])
+
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
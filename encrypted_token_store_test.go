@@ -0,0 +1,114 @@
+package czds_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestEncryptedTokenStore(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	for name, tc := range map[string]struct {
+		tamper        func(t *testing.T, underlying *czds.InMemoryTokenStore)
+		expectedToken string
+	}{
+		"Success": {
+			tamper:        func(t *testing.T, underlying *czds.InMemoryTokenStore) {},
+			expectedToken: testGoodToken,
+		},
+		"Success_TamperedCiphertext": {
+			tamper: func(t *testing.T, underlying *czds.InMemoryTokenStore) {
+				sealed, err := base64.RawURLEncoding.DecodeString(underlying.Get(context.Background()))
+				require.NoError(t, err)
+				sealed[len(sealed)-1] ^= 0xff
+				require.NoError(t, underlying.Save(context.Background(), base64.RawURLEncoding.EncodeToString(sealed)))
+			},
+		},
+		"Success_NotEncrypted": {
+			tamper: func(t *testing.T, underlying *czds.InMemoryTokenStore) {
+				require.NoError(t, underlying.Save(context.Background(), testGoodToken))
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			underlying := &czds.InMemoryTokenStore{}
+			store, err := czds.NewEncryptedTokenStore(underlying, key)
+			require.NoError(t, err)
+
+			require.NoError(t, store.Save(context.Background(), testGoodToken))
+			assert.NotContains(t, underlying.Get(context.Background()), testGoodToken)
+
+			tc.tamper(t, underlying)
+			assert.Equal(t, tc.expectedToken, store.Get(context.Background()))
+		})
+	}
+}
+
+func TestEncryptedTokenStore_Passphrase(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	underlying := &czds.InMemoryTokenStore{}
+
+	store, err := czds.NewEncryptedTokenStoreFromPassphrase(underlying, "test-passphrase", salt)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), testGoodToken))
+
+	sameKeyStore, err := czds.NewEncryptedTokenStoreFromPassphrase(underlying, "test-passphrase", salt)
+	require.NoError(t, err)
+	assert.Equal(t, testGoodToken, sameKeyStore.Get(context.Background()))
+
+	otherKeyStore, err := czds.NewEncryptedTokenStoreFromPassphrase(underlying, "other-passphrase", salt)
+	require.NoError(t, err)
+	assert.Empty(t, otherKeyStore.Get(context.Background()))
+
+	_, err = czds.NewEncryptedTokenStoreFromPassphrase(underlying, "test-passphrase", []byte("short"))
+	assert.Error(t, err)
+}
+
+func TestEncryptedTokenStore_InvalidKey(t *testing.T) {
+	_, err := czds.NewEncryptedTokenStore(&czds.InMemoryTokenStore{}, []byte("too-short"))
+	assert.Error(t, err)
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vectors from RFC 7914, section 11.
+	for name, tc := range map[string]struct {
+		password    string
+		salt        string
+		iterations  int
+		expectedKey string
+	}{
+		"OneIteration": {
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			expectedKey: "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+				"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		"ManyIterations": {
+			password:   "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			expectedKey: "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+				"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key := czds.PBKDF2SHA256([]byte(tc.password), []byte(tc.salt), tc.iterations, 64)
+			assert.Equal(t, tc.expectedKey, hex.EncodeToString(key))
+		})
+	}
+}
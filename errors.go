@@ -0,0 +1,187 @@
+package czds
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TLDNotAvailableError is returned when access is requested for TLDs which are not in the "available" state for the
+// account, e.g. because access was already requested or approved. Statuses maps each offending TLD to its current
+// status, which is empty if the TLD isn't offered by CZDS at all.
+type TLDNotAvailableError struct {
+	Statuses map[string]TLDStatus
+}
+
+func (e *TLDNotAvailableError) Error() string {
+	tlds := make([]string, 0, len(e.Statuses))
+	for tld, status := range e.Statuses {
+		if status == "" {
+			status = "unknown"
+		}
+		tlds = append(tlds, fmt.Sprintf("%s (%s)", tld, status))
+	}
+	sort.Strings(tlds)
+
+	return "TLDs not available for an access request: " + strings.Join(tlds, ", ")
+}
+
+// ErrActionNotAllowed is returned when an action can't be taken on an access request in its current status. It is
+// wrapped by *RequestActionError, which carries the details.
+var ErrActionNotAllowed = errors.New("action not allowed for the access request status")
+
+// RequestActionError is returned when an action, such as cancelling or extending, isn't allowed for the current
+// status of an access request.
+type RequestActionError struct {
+	RequestID string
+	Action    string
+	Status    TLDStatus
+}
+
+func (e *RequestActionError) Error() string {
+	return fmt.Sprintf("cannot %s access request %s in %q status", e.Action, e.RequestID, e.Status)
+}
+
+func (e *RequestActionError) Unwrap() error {
+	return ErrActionNotAllowed
+}
+
+// ErrTokenRejected is returned, wrapped by *APIError, when CZDS keeps rejecting the JWT even after
+// re-authenticating. It wraps ErrUnauthorized.
+var ErrTokenRejected = fmt.Errorf("JWT rejected after re-authentication: %w", ErrUnauthorized)
+
+// ErrFileLockUnsupported is returned by FileTokenLocker.Lock on platforms without advisory file locks, i.e. other
+// than unix and Windows, where it can't coordinate processes.
+var ErrFileLockUnsupported = errors.New("advisory file locks aren't supported on this platform")
+
+// ErrNoToken is returned by Client.Token when no JWT is stored, e.g. before the first request or after Logout.
+var ErrNoToken = errors.New("no JWT stored")
+
+// Errors describing why authenticating with the ICANN accounts API failed, wrapped by *AuthError.
+var (
+	// ErrInvalidCredentials means the email or password is wrong, e.g. because the password was rotated, or the
+	// credentials were rejected as malformed.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountLocked means the account is locked or disabled, or otherwise forbidden from authenticating.
+	ErrAccountLocked = errors.New("account locked or disabled")
+	// ErrAuthRateLimited means too many authentication requests were made, AuthError.RetryAfter holds how long
+	// to wait before retrying when the server provides it.
+	ErrAuthRateLimited = errors.New("authentication rate limited")
+	// ErrAuthServerFailure means the ICANN accounts API failed to process the authentication request.
+	ErrAuthServerFailure = errors.New("authentication server failure")
+)
+
+// ErrAuthLimitExceeded is returned, wrapped by *AuthLimitError, when the client-side authentication budget
+// configured with AuthRateLimit is exhausted.
+var ErrAuthLimitExceeded = errors.New("authentication limit exceeded")
+
+// AuthLimitError is returned instead of sending an authentication request which would exceed the client-side
+// authentication budget. RetryAt holds the time at which the next attempt is allowed.
+type AuthLimitError struct {
+	RetryAt time.Time
+}
+
+func (e *AuthLimitError) Error() string {
+	return fmt.Sprintf("%s, next attempt allowed at %s", ErrAuthLimitExceeded, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *AuthLimitError) Unwrap() error {
+	return ErrAuthLimitExceeded
+}
+
+// ErrQuotaExceeded is returned, wrapped by *QuotaExceededError, when downloading a zone file would exceed the
+// download quota configured with DownloadQuota.
+var ErrQuotaExceeded = errors.New("download quota exceeded")
+
+// QuotaExceededError is returned instead of downloading a zone file which would exceed the download quota of its
+// TLD. RetryAt holds the time at which the next download is allowed.
+type QuotaExceededError struct {
+	TLD     string
+	RetryAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s for %s TLD, next download allowed at %s", ErrQuotaExceeded, e.TLD,
+		e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// AuthError describes a failure to authenticate with the ICANN accounts API, carrying the message returned by the
+// server. Err holds the underlying cause, which can be matched with errors.Is.
+type AuthError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *AuthError) Error() string {
+	msg := fmt.Sprintf("authentication failed with HTTP %d", e.StatusCode)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Errors describing why a CZDS API request failed, wrapped by *APIError.
+var (
+	// ErrUnauthorized means CZDS rejected the JWT. As the client re-authenticates and replays such requests, it is
+	// usually returned wrapped by ErrTokenRejected.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden means the account isn't allowed to access the resource, e.g. the zone file of a TLD for which
+	// access isn't approved.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound means the resource doesn't exist, e.g. the zone file of a TLD which isn't offered by CZDS.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited means too many requests were made, APIError.RetryAfter holds how long to wait before retrying
+	// when the server provides it.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServerFailure means CZDS failed to process the request.
+	ErrServerFailure = errors.New("server failure")
+)
+
+// APIError describes a CZDS API request which didn't succeed, carrying the request ID and up to 1 KiB of the
+// response body to help diagnose it. Err holds the cause derived from the status code, if any, which can be
+// matched with errors.Is.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	RequestID  string
+	Body       string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s failed with HTTP %d", e.Method, e.URL, e.StatusCode)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
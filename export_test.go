@@ -0,0 +1,4 @@
+package czds
+
+// PBKDF2SHA256 exposes the key derivation of EncryptedTokenStore to the tests.
+var PBKDF2SHA256 = pbkdf2SHA256
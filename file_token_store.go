@@ -0,0 +1,114 @@
+package czds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileTokenStore implements TokenStore to persist the JWT in a file, so it can be reused across short-lived
+// processes such as CLI invocations and cron jobs. The file is readable only by its owner and is written
+// atomically. Access is serialised with an advisory file lock, so concurrent processes on the same host don't
+// clobber each other. On platforms without advisory file locks, i.e. other than unix and Windows, access isn't
+// serialised. A missing or corrupt file is treated as no token being stored.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore returns a FileTokenStore persisting the JWT at the given path. A lock file with the ".lock"
+// suffix is created next to it.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+type fileToken struct {
+	Token string `json:"token"`
+}
+
+// Save atomically writes the given JWT to the file.
+func (s *FileTokenStore) Save(ctx context.Context, token string) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token store directory: %w", err)
+	}
+
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to lock token store: %w", err)
+	}
+	defer unlock()
+
+	data, err := json.Marshal(&fileToken{Token: token})
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	return writeFileAtomic(s.path, data)
+}
+
+// Get reads the JWT from the file, returning an empty string if the file is missing, corrupt or can't be locked.
+func (s *FileTokenStore) Get(ctx context.Context) string {
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return ""
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return ""
+	}
+
+	var stored fileToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return ""
+	}
+
+	return stored.Token
+}
+
+// lock acquires the lock file of the store, carrying on without it on platforms without advisory file locks.
+func (s *FileTokenStore) lock(ctx context.Context, exclusive bool) (func(), error) {
+	unlock, err := lockFile(ctx, s.path+".lock", exclusive)
+	if errors.Is(err, ErrFileLockUnsupported) {
+		return func() {}, nil
+	}
+	return unlock, err
+}
+
+// writeFileAtomic writes data to a temporary file readable only by its owner and renames it over path, so readers
+// never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set temporary file permissions: %w", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
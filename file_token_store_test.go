@@ -0,0 +1,82 @@
+package czds_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	for name, tc := range map[string]struct {
+		setup         func(t *testing.T, path string)
+		expectedToken string
+	}{
+		"Success_SavedToken": {
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, czds.NewFileTokenStore(path).Save(context.Background(), testGoodToken))
+			},
+			expectedToken: testGoodToken,
+		},
+		"Success_MissingFile": {
+			setup: func(t *testing.T, path string) {},
+		},
+		"Success_CorruptFile": {
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+				require.NoError(t, os.WriteFile(path, []byte(`{"token":`), 0o600))
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "czds", "token.json")
+			tc.setup(t, path)
+
+			assert.Equal(t, tc.expectedToken, czds.NewFileTokenStore(path).Get(context.Background()))
+		})
+	}
+}
+
+func TestFileTokenStore_FilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "token.json")
+	require.NoError(t, czds.NewFileTokenStore(path).Save(context.Background(), testGoodToken))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestFileTokenStore_ConcurrentAccess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, czds.NewFileTokenStore(path).Save(context.Background(), testGoodToken))
+		}()
+		go func() {
+			defer wg.Done()
+			token := czds.NewFileTokenStore(path).Get(context.Background())
+			assert.Contains(t, []string{"", testGoodToken}, token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, testGoodToken, czds.NewFileTokenStore(path).Get(context.Background()))
+}
@@ -0,0 +1,44 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const fileLockRetryInterval = 10 * time.Millisecond
+
+// lockFile acquires an advisory exclusive or shared lock on the file at path, creating the file if needed. It
+// waits for the lock until the context is done. The returned function releases the lock. ErrFileLockUnsupported
+// is returned on platforms without advisory file locks.
+func lockFile(ctx context.Context, path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		locked, err := tryLockFile(f, exclusive)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock file: %w", err)
+		}
+
+		if locked {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+			return nil, ctx.Err()
+		case <-time.After(fileLockRetryInterval):
+		}
+	}
+
+	return func() {
+		unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix && !windows
+
+package czds
+
+import "os"
+
+// tryLockFile always fails, as advisory file locks aren't supported on this platform.
+func tryLockFile(_ *os.File, _ bool) (bool, error) {
+	return false, ErrFileLockUnsupported
+}
+
+func unlockFile(_ *os.File) {}
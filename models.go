@@ -1,13 +1,277 @@
 package czds
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 type authResponse struct {
 	AccessToken string `json:"accessToken"`
 	Message     string `json:"message"`
 }
 
 type TLD struct {
-	Name          string `json:"tld"`
-	Ulable        string `json:"ulable"`
-	CurrentStatus string `json:"currentStatus"`
-	SFTP          bool   `json:"sftp"`
+	Name          string    `json:"tld"`
+	Ulable        string    `json:"ulable"`
+	CurrentStatus TLDStatus `json:"currentStatus"`
+	SFTP          bool      `json:"sftp"`
+}
+
+// TermsAndConditions holds a version of the CZDS terms and conditions, which have to be accepted when requesting
+// access to zone files.
+type TermsAndConditions struct {
+	Version    string
+	Published  time.Time
+	Content    string
+	ContentURL string
+}
+
+// UnmarshalJSON decodes the terms and conditions as returned by CZDS.
+func (t *TermsAndConditions) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Version    string    `json:"version"`
+		Created    timestamp `json:"created"`
+		Content    string    `json:"content"`
+		ContentURL string    `json:"contentUrl"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = TermsAndConditions{
+		Version:    raw.Version,
+		Published:  time.Time(raw.Created),
+		Content:    raw.Content,
+		ContentURL: raw.ContentURL,
+	}
+	return nil
+}
+
+type createAccessRequestBody struct {
+	AllTLDs   bool     `json:"allTlds"`
+	TLDNames  []string `json:"tldNames"`
+	Reason    string   `json:"reason"`
+	TCVersion string   `json:"tcVersion"`
+}
+
+// AccessRequestResult describes a zone access request submitted to CZDS. Requests holds the access requests
+// created by CZDS, whose IDs can be used to track, cancel or extend them.
+type AccessRequestResult struct {
+	TLDs         []string
+	Reason       string
+	TermsVersion string
+	Requests     []AccessRequest
+}
+
+type listAccessRequestsBody struct {
+	Status     string            `json:"status"`
+	Filter     string            `json:"filter"`
+	Pagination paginationOptions `json:"pagination"`
+	Sort       sortOptions       `json:"sort"`
+}
+
+type paginationOptions struct {
+	Size int `json:"size"`
+	Page int `json:"page"`
+}
+
+type sortOptions struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+type listAccessRequestsResponse struct {
+	Requests      []AccessRequest `json:"requests"`
+	TotalRequests int             `json:"totalRequests"`
+}
+
+// AccessRequestFilter narrows down and orders the access requests returned by ListAccessRequests.
+type AccessRequestFilter struct {
+	// Status limits the results to requests with the given status. All requests are returned when empty.
+	Status TLDStatus
+	// Filter limits the results to requests whose TLD matches the given text.
+	Filter string
+	// Page is the zero-based page number.
+	Page int
+	// PageSize is the number of requests per page, defaulting to 100.
+	PageSize int
+	// SortField is the field to sort by, defaulting to "created".
+	SortField string
+	// SortDirection is either "asc" or "desc", defaulting to "desc".
+	SortDirection string
+}
+
+// AccessRequestPage holds a single page of access requests.
+type AccessRequestPage struct {
+	Requests []AccessRequest
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// AccessRequest represents a zone file access request submitted for a TLD.
+type AccessRequest struct {
+	RequestID   string
+	TLD         string
+	ULabel      string
+	Status      TLDStatus
+	Created     time.Time
+	LastUpdated time.Time
+	Expired     time.Time
+	SFTP        bool
+}
+
+// UnmarshalJSON decodes an access request as returned by CZDS.
+func (r *AccessRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		RequestID   string    `json:"requestId"`
+		TLD         string    `json:"tld"`
+		ULabel      string    `json:"ulabel"`
+		Status      TLDStatus `json:"status"`
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"last_updated"`
+		Expired     timestamp `json:"expired"`
+		SFTP        bool      `json:"sftp"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = AccessRequest{
+		RequestID:   raw.RequestID,
+		TLD:         raw.TLD,
+		ULabel:      raw.ULabel,
+		Status:      raw.Status,
+		Created:     time.Time(raw.Created),
+		LastUpdated: time.Time(raw.LastUpdated),
+		Expired:     time.Time(raw.Expired),
+		SFTP:        raw.SFTP,
+	}
+	return nil
+}
+
+// AccessRequestDetail holds the full detail of an access request, including the reason given when submitting it
+// and the history of the actions taken on it.
+type AccessRequestDetail struct {
+	AccessRequest
+	Reason       string
+	TermsVersion string
+	History      []AccessRequestEvent
+}
+
+// AccessRequestEvent is a single entry in the history of an access request.
+type AccessRequestEvent struct {
+	Timestamp time.Time
+	Action    string
+	Comment   string
+}
+
+// UnmarshalJSON decodes the access request detail as returned by CZDS, where the TLD is given either by name or
+// as a TLD object.
+func (d *AccessRequestDetail) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		RequestID   string          `json:"requestId"`
+		TLD         json.RawMessage `json:"tld"`
+		Status      TLDStatus       `json:"status"`
+		Created     timestamp       `json:"created"`
+		LastUpdated timestamp       `json:"last_updated"`
+		Expired     timestamp       `json:"expired"`
+		SFTP        bool            `json:"sftp"`
+		Reason      string          `json:"reason"`
+		TCVersion   string          `json:"tcVersion"`
+		History     []struct {
+			Timestamp timestamp `json:"timestamp"`
+			Action    string    `json:"action"`
+			Comment   string    `json:"comment"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var tld TLD
+	if len(raw.TLD) > 0 && raw.TLD[0] == '"' {
+		if err := json.Unmarshal(raw.TLD, &tld.Name); err != nil {
+			return err
+		}
+		tld.Ulable = tld.Name
+	} else if len(raw.TLD) > 0 {
+		if err := json.Unmarshal(raw.TLD, &tld); err != nil {
+			return err
+		}
+	}
+
+	*d = AccessRequestDetail{
+		AccessRequest: AccessRequest{
+			RequestID:   raw.RequestID,
+			TLD:         tld.Name,
+			ULabel:      tld.Ulable,
+			Status:      raw.Status,
+			Created:     time.Time(raw.Created),
+			LastUpdated: time.Time(raw.LastUpdated),
+			Expired:     time.Time(raw.Expired),
+			SFTP:        raw.SFTP,
+		},
+		Reason:       raw.Reason,
+		TermsVersion: raw.TCVersion,
+	}
+	for _, event := range raw.History {
+		d.History = append(d.History, AccessRequestEvent{
+			Timestamp: time.Time(event.Timestamp),
+			Action:    event.Action,
+			Comment:   event.Comment,
+		})
+	}
+	return nil
+}
+
+type cancelAccessRequestBody struct {
+	IntegrationID string `json:"integrationId"`
+	TLDName       string `json:"tldName"`
+}
+
+// timestampLayouts lists the layouts CZDS is known to use for dates and times.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// timestamp decodes the dates and times returned by CZDS, which are either strings in one of the timestampLayouts
+// or milliseconds since the Unix epoch. Null and empty values decode to the zero time.
+type timestamp time.Time
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = timestamp{}
+		return nil
+	}
+
+	var millis int64
+	if err := json.Unmarshal(data, &millis); err == nil {
+		*t = timestamp(time.UnixMilli(millis).UTC())
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to decode timestamp: %w", err)
+	}
+
+	if value == "" {
+		*t = timestamp{}
+		return nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*t = timestamp(parsed)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported timestamp format %q", value)
 }
@@ -0,0 +1,98 @@
+package czds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultExpiryWindow = 30 * 24 * time.Hour
+
+// ExpiryMonitorOptions holds the configuration for CheckApprovalExpiry.
+type ExpiryMonitorOptions struct {
+	window     time.Duration
+	autoExtend bool
+}
+
+type ExpiryMonitorOption func(*ExpiryMonitorOptions)
+
+// ExpiryWindow sets how far ahead approvals are considered to be expiring. Defaults to 30 days.
+func ExpiryWindow(window time.Duration) ExpiryMonitorOption {
+	return func(opts *ExpiryMonitorOptions) {
+		opts.window = window
+	}
+}
+
+// AutoExtend makes CheckApprovalExpiry extend every expiring approval.
+func AutoExtend() ExpiryMonitorOption {
+	return func(opts *ExpiryMonitorOptions) {
+		opts.autoExtend = true
+	}
+}
+
+// ExpiryReport describes the approvals found to be expiring by CheckApprovalExpiry and, when auto-extension is
+// enabled, the outcome of extending each of them.
+type ExpiryReport struct {
+	CheckedAt time.Time
+	// Expiring holds every approved access request expiring within the window.
+	Expiring []AccessRequest
+	// Extended holds the expiring access requests which were successfully extended.
+	Extended []AccessRequest
+	// Failed holds the expiring access requests which failed to be extended.
+	Failed []ExtensionFailure
+	// NotExtendable holds the expiring access requests which can't be extended in their current status.
+	NotExtendable []ExtensionFailure
+}
+
+// ExtensionFailure describes why an access request wasn't extended.
+type ExtensionFailure struct {
+	AccessRequest AccessRequest
+	Err           error
+}
+
+// CheckApprovalExpiry lists the approved access requests and reports those expiring within the configured window,
+// optionally extending each of them. It holds no state between calls and extended approvals fall out of the
+// window, so it is safe to run on a schedule. An error is returned only if the access requests can't be listed;
+// failures to extend individual requests are recorded in the report.
+func (c *Client) CheckApprovalExpiry(ctx context.Context, opts ...ExpiryMonitorOption) (*ExpiryReport, error) {
+	options := &ExpiryMonitorOptions{
+		window: defaultExpiryWindow,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	report := &ExpiryReport{CheckedAt: time.Now().UTC()}
+	deadline := report.CheckedAt.Add(options.window)
+
+	it := c.AccessRequests(AccessRequestFilter{Status: TLDStatusApproved})
+	for it.Next(ctx) {
+		req := it.AccessRequest()
+		if req.Status != TLDStatusApproved || req.Expired.IsZero() || req.Expired.After(deadline) {
+			continue
+		}
+		report.Expiring = append(report.Expiring, req)
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+
+	if !options.autoExtend {
+		return report, nil
+	}
+
+	for _, req := range report.Expiring {
+		err := c.ExtendAccessRequest(ctx, req.RequestID)
+		switch {
+		case err == nil:
+			report.Extended = append(report.Extended, req)
+		case errors.Is(err, ErrActionNotAllowed):
+			report.NotExtendable = append(report.NotExtendable, ExtensionFailure{AccessRequest: req, Err: err})
+		default:
+			report.Failed = append(report.Failed, ExtensionFailure{AccessRequest: req, Err: err})
+		}
+	}
+
+	return report, nil
+}
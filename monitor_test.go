@@ -0,0 +1,108 @@
+package czds_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestCheckApprovalExpiry(t *testing.T) {
+	now := time.Now().UTC()
+	requests := []string{
+		fmt.Sprintf(`{"requestId":"id-1","tld":"dev","status":"Approved","expired":%q}`,
+			now.Add(24*time.Hour).Format(time.RFC3339)),
+		fmt.Sprintf(`{"requestId":"id-2","tld":"tech","status":"Approved","expired":%q}`,
+			now.Add(48*time.Hour).Format(time.RFC3339)),
+		fmt.Sprintf(`{"requestId":"id-3","tld":"com","status":"Approved","expired":%q}`,
+			now.Add(72*time.Hour).Format(time.RFC3339)),
+		fmt.Sprintf(`{"requestId":"id-4","tld":"net","status":"Approved","expired":%q}`,
+			now.Add(90*24*time.Hour).Format(time.RFC3339)),
+	}
+	statuses := map[string]string{"id-1": "Approved", "id-2": "Approved", "id-3": "Revoked"}
+
+	for name, tc := range map[string]struct {
+		opts                  []czds.ExpiryMonitorOption
+		expectedExpiring      []string
+		expectedExtended      []string
+		expectedFailed        []string
+		expectedNotExtendable []string
+	}{
+		"Success_ReportOnly": {
+			expectedExpiring: []string{"id-1", "id-2", "id-3"},
+		},
+		"Success_Window": {
+			opts:             []czds.ExpiryMonitorOption{czds.ExpiryWindow(36 * time.Hour)},
+			expectedExpiring: []string{"id-1"},
+		},
+		"Success_AutoExtend": {
+			opts:                  []czds.ExpiryMonitorOption{czds.AutoExtend()},
+			expectedExpiring:      []string{"id-1", "id-2", "id-3"},
+			expectedExtended:      []string{"id-1"},
+			expectedFailed:        []string{"id-2"},
+			expectedNotExtendable: []string{"id-3"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /requests/all", func(w http.ResponseWriter, r *http.Request) {
+				_, err := fmt.Fprintf(w, `{"requests":[%s],"totalRequests":%d}`,
+					strings.Join(requests, ","), len(requests))
+				require.NoError(t, err)
+			})
+			mux.HandleFunc("GET /requests/{id}", func(w http.ResponseWriter, r *http.Request) {
+				_, err := fmt.Fprintf(w, `{"requestId":%q,"status":%q}`, r.PathValue("id"), statuses[r.PathValue("id")])
+				require.NoError(t, err)
+			})
+			mux.HandleFunc("POST /requests/extension/{id}", func(w http.ResponseWriter, r *http.Request) {
+				if r.PathValue("id") == "id-2" {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			})
+			mockCZDSAPI := httptest.NewServer(mux)
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			report, err := client.CheckApprovalExpiry(context.Background(), tc.opts...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedExpiring, requestIDs(report.Expiring))
+			assert.Equal(t, tc.expectedExtended, requestIDs(report.Extended))
+			assert.Equal(t, tc.expectedFailed, failureIDs(report.Failed))
+			assert.Equal(t, tc.expectedNotExtendable, failureIDs(report.NotExtendable))
+		})
+	}
+}
+
+func requestIDs(requests []czds.AccessRequest) []string {
+	var ids []string
+	for _, req := range requests {
+		ids = append(ids, req.RequestID)
+	}
+	return ids
+}
+
+func failureIDs(failures []czds.ExtensionFailure) []string {
+	var ids []string
+	for _, failure := range failures {
+		ids = append(ids, failure.AccessRequest.RequestID)
+	}
+	return ids
+}
@@ -1,19 +1,223 @@
 package czds
 
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
+
 type Options struct {
-	tokenStore         TokenStore
-	accountsAPIBaseURL string
-	czdsAPIBaseURL     string
+	tokenStore             TokenStore
+	tokenStoreV2           TokenStoreV2
+	tokenLocker            TokenLocker
+	credentialsProvider    CredentialsProvider
+	authLimit              int
+	authLimitWindow        time.Duration
+	authLimitWait          bool
+	authAttemptStore       AuthAttemptStore
+	authTimeout            time.Duration
+	authHTTPClient         *http.Client
+	httpClient             *http.Client
+	transport              http.RoundTripper
+	retryPolicy            *RetryPolicy
+	requestInterval        time.Duration
+	requestBurst           int
+	downloadLimit          int
+	downloadLimitWindow    time.Duration
+	quotaStore             QuotaStore
+	accountsAPIBaseURL     string
+	czdsAPIBaseURL         string
+	tokenRefreshSkew       time.Duration
+	backgroundRefreshEvery time.Duration
 }
 
 type ClientOption func(*Options)
 
+// getTokenStore returns the configured token store, preferring a TokenStoreV2 over a TokenStore and defaulting to
+// an in-memory store.
+func (o *Options) getTokenStore() TokenStoreV2 {
+	switch {
+	case o.tokenStoreV2 != nil:
+		return o.tokenStoreV2
+	case o.tokenStore != nil:
+		return AdaptTokenStore(o.tokenStore)
+	default:
+		return AdaptTokenStore(&InMemoryTokenStore{})
+	}
+}
+
+// getHTTPClients returns the HTTP client for CZDS requests, whose transport is yet to be wrapped by the auth layer,
+// the base transport the auth layer sends requests with, and the HTTP client for authentication requests.
+func (o *Options) getHTTPClients() (httpClient *http.Client, base http.RoundTripper, authHTTPClient *http.Client) {
+	httpClient = &http.Client{}
+	if o.httpClient != nil {
+		configured := *o.httpClient
+		httpClient = &configured
+	}
+
+	base = http.DefaultTransport
+	switch {
+	case o.transport != nil:
+		base = o.transport
+	case httpClient.Transport != nil:
+		base = httpClient.Transport
+	}
+
+	authHTTPClient = o.authHTTPClient
+	if authHTTPClient == nil {
+		configured := *httpClient
+		configured.Transport = base
+		authHTTPClient = &configured
+	}
+
+	return httpClient, base, authHTTPClient
+}
+
+// withRetries wraps the given transport to retry requests according to the configured retry policy, if any.
+func (o *Options) withRetries(next http.RoundTripper) http.RoundTripper {
+	if o.retryPolicy == nil {
+		return next
+	}
+	return &retryTransport{next: next, policy: o.retryPolicy.withDefaults()}
+}
+
+// withRequestRateLimit wraps the given transport to enforce the configured request rate, if any.
+func (o *Options) withRequestRateLimit(next http.RoundTripper) http.RoundTripper {
+	if o.requestInterval <= 0 {
+		return next
+	}
+	return &rateLimitTransport{next: next, bucket: newTokenBucket(o.requestInterval, o.requestBurst)}
+}
+
+// withDownloadQuota wraps the given transport to enforce the configured download quota, if any.
+func (o *Options) withDownloadQuota(next http.RoundTripper) http.RoundTripper {
+	if o.downloadLimit <= 0 {
+		return next
+	}
+
+	store := o.quotaStore
+	if store == nil {
+		store = &InMemoryQuotaStore{}
+	}
+
+	window := o.downloadLimitWindow
+	if window <= 0 {
+		window = defaultDownloadLimitWindow
+	}
+
+	return &quotaTransport{next: next, quota: &downloadQuota{store: store, limit: o.downloadLimit, window: window}}
+}
+
+// getAuthLimiter returns the configured authentication limiter, or nil if authentication isn't limited.
+func (o *Options) getAuthLimiter() *authLimiter {
+	if o.authLimit <= 0 {
+		return nil
+	}
+
+	store := o.authAttemptStore
+	if store == nil {
+		store = &InMemoryAuthAttemptStore{}
+	}
+
+	return &authLimiter{
+		store:  store,
+		limit:  o.authLimit,
+		window: o.authLimitWindow,
+		wait:   o.authLimitWait,
+	}
+}
+
 func TokenStoreOpt(store TokenStore) ClientOption {
 	return func(opts *Options) {
 		opts.tokenStore = store
 	}
 }
 
+// TokenStoreV2Opt sets a TokenStoreV2 to store the JWT in. It is preferred over any TokenStore set via
+// TokenStoreOpt.
+func TokenStoreV2Opt(store TokenStoreV2) ClientOption {
+	return func(opts *Options) {
+		opts.tokenStoreV2 = store
+	}
+}
+
+// TokenLockerOpt sets a TokenLocker held while refreshing the JWT, so processes sharing a token store coordinate
+// on a single refresh, with the others waiting for the lock and reusing the JWT it stored.
+func TokenLockerOpt(locker TokenLocker) ClientOption {
+	return func(opts *Options) {
+		opts.tokenLocker = locker
+	}
+}
+
+// CredentialsProviderOpt sets the CredentialsProvider consulted for the email and password every time the client
+// authenticates, taking precedence over the email and password given to NewClient.
+func CredentialsProviderOpt(provider CredentialsProvider) ClientOption {
+	return func(opts *Options) {
+		opts.credentialsProvider = provider
+	}
+}
+
+// AuthRateLimit limits the client to at most limit authentication requests within any window, so it doesn't trip
+// the ICANN accounts API rate limit and get locked out. Once the budget is exhausted, authentication fails fast
+// with an *AuthLimitError unless AuthRateLimitWait is provided. The window must be positive, otherwise every
+// authentication fails.
+func AuthRateLimit(limit int, window time.Duration) ClientOption {
+	return func(opts *Options) {
+		opts.authLimit = limit
+		opts.authLimitWindow = window
+	}
+}
+
+// AuthRateLimitWait makes the client wait for the authentication budget to allow another attempt, for as long as
+// the request context allows, instead of failing fast.
+func AuthRateLimitWait() ClientOption {
+	return func(opts *Options) {
+		opts.authLimitWait = true
+	}
+}
+
+// AuthAttemptStoreOpt sets the AuthAttemptStore tracking the authentication budget configured with AuthRateLimit.
+// A shared store allows multiple processes to cooperate on the same budget. Defaults to an in-memory store.
+func AuthAttemptStoreOpt(store AuthAttemptStore) ClientOption {
+	return func(opts *Options) {
+		opts.authAttemptStore = store
+	}
+}
+
+// AuthTimeout sets how long an authentication request may take, independently of the timeouts of the requests
+// waiting for it. Defaults to 30 seconds, while zero disables the timeout.
+func AuthTimeout(timeout time.Duration) ClientOption {
+	return func(opts *Options) {
+		opts.authTimeout = timeout
+	}
+}
+
+// AuthHTTPClient sets the HTTP client used to send authentication requests to the ICANN accounts API, overriding
+// HTTPClient and HTTPTransport for authentication.
+func AuthHTTPClient(httpClient *http.Client) ClientOption {
+	return func(opts *Options) {
+		opts.authHTTPClient = httpClient
+	}
+}
+
+// HTTPClient sets the HTTP client configuration, such as timeouts, redirect policy and cookie jar, used for every
+// request, including authentication unless AuthHTTPClient is provided. The client is copied and its transport is
+// wrapped by the auth layer, so the given client is left untouched.
+func HTTPClient(httpClient *http.Client) ClientOption {
+	return func(opts *Options) {
+		opts.httpClient = httpClient
+	}
+}
+
+// HTTPTransport sets the base transport used for every request, including authentication unless AuthHTTPClient
+// is provided, e.g. to configure a proxy, a custom CA pool, mTLS or connection limits. It takes precedence over
+// the transport of the client set via HTTPClient. Defaults to http.DefaultTransport.
+func HTTPTransport(transport http.RoundTripper) ClientOption {
+	return func(opts *Options) {
+		opts.transport = transport
+	}
+}
+
 func ICANNAccountsAPIBaseURL(baseURL string) ClientOption {
 	return func(opts *Options) {
 		opts.accountsAPIBaseURL = baseURL
@@ -25,3 +229,124 @@ func APIBaseURL(baseURL string) ClientOption {
 		opts.czdsAPIBaseURL = baseURL
 	}
 }
+
+// TokenRefreshSkew makes the client refresh the JWT once less than the given duration remains before it expires,
+// rather than at the exact expiry time. This prevents long-running downloads from failing mid-flight and accounts
+// for clock skew between the client and ICANN. The skew is capped at half the lifetime of the JWT, so a skew longer
+// than the JWT lives doesn't make every request authenticate again.
+func TokenRefreshSkew(skew time.Duration) ClientOption {
+	return func(opts *Options) {
+		opts.tokenRefreshSkew = skew
+	}
+}
+
+// BackgroundTokenRefresh makes the client check the stored JWT every interval and refresh it in the background
+// once it expires within twice the interval, or within the skew set with TokenRefreshSkew if longer, so
+// long-running workers always hold a fresh JWT. Client.Close stops the refresh.
+func BackgroundTokenRefresh(interval time.Duration) ClientOption {
+	return func(opts *Options) {
+		opts.backgroundRefreshEvery = interval
+	}
+}
+
+// ParseOptions holds the filters applied while parsing a zone file.
+type ParseOptions struct {
+	recordTypes  [][]byte
+	ownerFilters []func(owner string) bool
+}
+
+type ParseOption func(*ParseOptions)
+
+// RecordTypes keeps only the records of the given types, e.g. "ns" or "ds". Types are matched case-insensitively.
+func RecordTypes(types ...string) ParseOption {
+	return func(opts *ParseOptions) {
+		for _, t := range types {
+			opts.recordTypes = append(opts.recordTypes, []byte(t))
+		}
+	}
+}
+
+// OwnerFilter keeps only the records whose owner name satisfies the given predicate. When provided multiple
+// times, a record is kept only if it satisfies every predicate.
+func OwnerFilter(keep func(owner string) bool) ParseOption {
+	return func(opts *ParseOptions) {
+		opts.ownerFilters = append(opts.ownerFilters, keep)
+	}
+}
+
+// keepType reports whether a record should be kept based on its type, given the part of the line following
+// the owner name.
+func (o *ParseOptions) keepType(rest []byte) bool {
+	if len(o.recordTypes) == 0 {
+		return true
+	}
+
+	// The record type is the third field following the owner name, after the TTL and class.
+	for i := 0; i < 2; i++ {
+		idx := bytes.IndexByte(rest, '\t')
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+1:]
+	}
+
+	if idx := bytes.IndexByte(rest, '\t'); idx >= 0 {
+		rest = rest[:idx]
+	}
+
+	for _, t := range o.recordTypes {
+		if bytes.EqualFold(rest, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *ParseOptions) keepOwner(owner string) bool {
+	for _, keep := range o.ownerFilters {
+		if !keep(owner) {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryPolicyOpt makes the client retry requests to CZDS which fail with a transient error or a retryable HTTP
+// status according to the given policy, and resume interrupted zone file downloads with range requests where the
+// server supports them. Requests aren't retried by default. Start from DefaultRetryPolicy to adjust the defaults.
+func RetryPolicyOpt(policy RetryPolicy) ClientOption {
+	return func(opts *Options) {
+		opts.retryPolicy = &policy
+	}
+}
+
+// RequestRateLimit limits the requests sent to CZDS to one per interval on average, allowing bursts of up to burst
+// requests. Requests over the limit wait for their turn, for as long as their context allows. Each retry counts as
+// a request.
+func RequestRateLimit(interval time.Duration, burst int) ClientOption {
+	return func(opts *Options) {
+		opts.requestInterval = interval
+		opts.requestBurst = burst
+	}
+}
+
+// DownloadQuota limits the zone file downloads of each TLD to at most limit within any window, e.g. to stay within
+// the CZDS download policy. Every download request sent to CZDS counts, including retries and resumed downloads,
+// but not requests which fail to authenticate or are cancelled before being sent. Once the quota of a TLD is
+// exhausted, downloading its zone file fails fast with a *QuotaExceededError, without sending the request. A
+// window which isn't positive defaults to 24 hours.
+func DownloadQuota(limit int, window time.Duration) ClientOption {
+	return func(opts *Options) {
+		opts.downloadLimit = limit
+		opts.downloadLimitWindow = window
+	}
+}
+
+// QuotaStoreOpt sets the QuotaStore tracking the download quota configured with DownloadQuota. A shared store
+// allows multiple processes to cooperate on the same quota. Defaults to an in-memory store.
+func QuotaStoreOpt(store QuotaStore) ClientOption {
+	return func(opts *Options) {
+		opts.quotaStore = store
+	}
+}
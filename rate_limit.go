@@ -0,0 +1,179 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuotaStore records the zone file downloads made per TLD, so the download quota configured with DownloadQuota can
+// be enforced. Backing it by a shared store, such as Redis or a database, allows multiple processes downloading
+// with the same account to cooperate on the same quota.
+type QuotaStore interface {
+	// Reserve records a download of the TLD identified by key made at now, provided fewer than limit downloads
+	// were recorded for it within the window ending at now. Otherwise, it records nothing and returns the time at
+	// which the next download is allowed.
+	Reserve(ctx context.Context, key string, now time.Time, limit int, window time.Duration) (allowed bool,
+		retryAt time.Time, err error)
+}
+
+// InMemoryQuotaStore implements QuotaStore to record downloads in memory, limiting only the downloads made by the
+// current process.
+type InMemoryQuotaStore struct {
+	logs map[string]*slidingWindowLog
+	mu   sync.Mutex
+}
+
+// Reserve records a download of the TLD at now if its quota allows it.
+func (s *InMemoryQuotaStore) Reserve(_ context.Context, key string, now time.Time, limit int,
+	window time.Duration) (bool, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logs == nil {
+		s.logs = make(map[string]*slidingWindowLog)
+	}
+
+	log, ok := s.logs[key]
+	if !ok {
+		log = &slidingWindowLog{}
+		s.logs[key] = log
+	}
+
+	allowed, retryAt := log.reserve(now, limit, window)
+	return allowed, retryAt, nil
+}
+
+// defaultDownloadLimitWindow matches the CZDS policy of downloading each zone file at most once every 24 hours.
+const defaultDownloadLimitWindow = 24 * time.Hour
+
+// downloadQuota enforces the per-TLD download quota, failing fast with a *QuotaExceededError.
+type downloadQuota struct {
+	store  QuotaStore
+	limit  int
+	window time.Duration
+}
+
+func (q *downloadQuota) reserve(ctx context.Context, tld string) error {
+	tld = normaliseDomain(tld)
+
+	allowed, retryAt, err := q.store.Reserve(ctx, tld, time.Now(), q.limit, q.window)
+	if err != nil {
+		return fmt.Errorf("failed to reserve %s zone file download: %w", tld, err)
+	}
+
+	if !allowed {
+		return &QuotaExceededError{TLD: tld, RetryAt: retryAt}
+	}
+
+	return nil
+}
+
+// quotaTransport reserves the download quota of the TLD for every zone file download sent through the next
+// transport, retries and resumed downloads included, failing fast with a *QuotaExceededError once it is exhausted.
+// It wraps the base transport of the auth layer, so only requests actually sent to CZDS count, not those which
+// failed to authenticate or were cancelled while waiting for the request rate limit.
+type quotaTransport struct {
+	next  http.RoundTripper
+	quota *downloadQuota
+}
+
+func (t *quotaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tld, ok := downloadTLD(req); ok {
+		if err := t.quota.reserve(req.Context(), tld); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// downloadTLD returns the TLD of the zone file requested by a GET request to /downloads/{tld}.zone.
+func downloadTLD(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet {
+		return "", false
+	}
+
+	dir, file := path.Split(req.URL.Path)
+	if !strings.HasSuffix(dir, "/downloads/") {
+		return "", false
+	}
+	return strings.CutSuffix(file, ".zone")
+}
+
+// tokenBucket allows events at a steady rate of one per interval, with bursts of up to burst events.
+type tokenBucket struct {
+	interval time.Duration
+	burst    int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(interval time.Duration, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{interval: interval, burst: burst, tokens: float64(burst)}
+}
+
+// reserve takes a token at now and returns how long to wait until it is available. Tokens are taken even when
+// none are left, so waiting callers are served in order.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+	}
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.interval))
+}
+
+// cancel returns a token which was reserved but not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+}
+
+// wait takes a token, waiting for it to be available until the context is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
+
+// rateLimitTransport delays the requests sent through the next transport, so they don't exceed the configured
+// request rate.
+type rateLimitTransport struct {
+	next   http.RoundTripper
+	bucket *tokenBucket
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.bucket.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
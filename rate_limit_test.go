@@ -0,0 +1,269 @@
+package czds_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestDownloadQuota(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	var downloads atomic.Int32
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		_, err := w.Write([]byte(testZoneFile))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL),
+		czds.DownloadQuota(2, 24*time.Hour))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetZoneFile(context.Background(), "com")
+		require.NoError(t, err)
+	}
+
+	_, err := client.GetZoneFile(context.Background(), "COM.")
+	require.ErrorIs(t, err, czds.ErrQuotaExceeded)
+
+	var quotaErr *czds.QuotaExceededError
+	require.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, "com", quotaErr.TLD)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), quotaErr.RetryAt, time.Minute)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	_, err = client.GetZoneFile(context.Background(), "net")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), downloads.Load())
+}
+
+func TestDownloadQuota_CountsRetries(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	var downloads atomic.Int32
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL),
+		czds.DownloadQuota(2, 24*time.Hour),
+		czds.RetryPolicyOpt(czds.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}))
+
+	_, err := client.GetZoneFile(context.Background(), "com")
+	assert.ErrorIs(t, err, czds.ErrQuotaExceeded)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	_, err = client.GetZoneFile(context.Background(), "com")
+	assert.ErrorIs(t, err, czds.ErrQuotaExceeded)
+	assert.Equal(t, int32(2), downloads.Load())
+}
+
+func TestDownloadQuota_OnlyCountsRequestsSentToCZDS(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts          []czds.ClientOption
+		failFirstAuth bool
+		prepare       func(t *testing.T, client *czds.Client)
+		ctx           func() (context.Context, context.CancelFunc)
+		errAssert     assert.ErrorAssertionFunc
+	}{
+		"AuthenticationFailure": {
+			failFirstAuth: true,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrAuthServerFailure)
+			},
+		},
+		"CancelledWhileRateLimited": {
+			opts: []czds.ClientOption{czds.RequestRateLimit(100*time.Millisecond, 1)},
+			prepare: func(t *testing.T, client *czds.Client) {
+				_, err := client.ListTLDs(context.Background())
+				require.NoError(t, err)
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if authCalls.Add(1) == 1 && tc.failFirstAuth {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+				_, err := w.Write([]byte(testResponse))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			var downloads atomic.Int32
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/tlds" {
+					_, err := w.Write([]byte(testTLDsResponse))
+					require.NoError(t, err)
+					return
+				}
+
+				downloads.Add(1)
+				_, err := w.Write([]byte(testZoneFile))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword, append(tc.opts,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL),
+				czds.DownloadQuota(1, 24*time.Hour))...)
+			if tc.prepare != nil {
+				tc.prepare(t, client)
+			}
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			_, err := client.GetZoneFile(ctx, "com")
+			tc.errAssert(t, err)
+			assert.Equal(t, int32(0), downloads.Load())
+
+			_, err = client.GetZoneFile(context.Background(), "com")
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), downloads.Load())
+		})
+	}
+}
+
+func TestDownloadQuota_DefaultWindow(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(testZoneFile))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL),
+		czds.DownloadQuota(1, 0))
+
+	_, err := client.GetZoneFile(context.Background(), "com")
+	require.NoError(t, err)
+
+	_, err = client.GetZoneFile(context.Background(), "com")
+	require.ErrorIs(t, err, czds.ErrQuotaExceeded)
+
+	var quotaErr *czds.QuotaExceededError
+	require.True(t, errors.As(err, &quotaErr))
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), quotaErr.RetryAt, time.Minute)
+}
+
+func TestDownloadQuota_SharedStore(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(testZoneFile))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	store := &czds.InMemoryQuotaStore{}
+	newClient := func() *czds.Client {
+		return czds.NewClient(testEmail, testPassword,
+			czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+			czds.APIBaseURL(mockCZDSAPI.URL),
+			czds.DownloadQuota(1, 24*time.Hour),
+			czds.QuotaStoreOpt(store))
+	}
+
+	_, err := newClient().GetZoneFile(context.Background(), "com")
+	require.NoError(t, err)
+
+	_, err = newClient().GetZoneFile(context.Background(), "com")
+	assert.ErrorIs(t, err, czds.ErrQuotaExceeded)
+}
+
+func TestRequestRateLimit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		timeout   time.Duration
+		requests  int
+		minTime   time.Duration
+		errAssert assert.ErrorAssertionFunc
+	}{
+		"Success_WaitsForTurn": {
+			timeout:   time.Second,
+			requests:  4,
+			minTime:   100 * time.Millisecond,
+			errAssert: assert.NoError,
+		},
+		"Fail_ContextDone": {
+			timeout:  20 * time.Millisecond,
+			requests: 4,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(testTLDsResponse))
+				require.NoError(t, err)
+			}))
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL),
+				czds.RequestRateLimit(50*time.Millisecond, 2))
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+
+			start := time.Now()
+			var err error
+			for i := 0; i < tc.requests && err == nil; i++ {
+				_, err = client.ListTLDs(ctx)
+			}
+
+			tc.errAssert(t, err)
+			assert.GreaterOrEqual(t, time.Since(start), tc.minTime)
+		})
+	}
+}
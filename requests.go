@@ -0,0 +1,252 @@
+package czds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// RequestAccess submits a zone file access request for the given TLDs along with the reason for requesting access.
+// The request references the current version of the CZDS terms and conditions, which are accepted on submission.
+// TLD names are matched case-insensitively and submitted lower-cased. Every TLD must be in the "available" state as
+// reported by ListTLDs, otherwise a *TLDNotAvailableError is returned and nothing is submitted.
+func (c *Client) RequestAccess(ctx context.Context, tlds []string, reason string) (*AccessRequestResult, error) {
+	if len(tlds) == 0 {
+		return nil, errors.New("at least one TLD is required")
+	}
+
+	if reason == "" {
+		return nil, errors.New("a reason for requesting access is required")
+	}
+
+	normalised := make([]string, 0, len(tlds))
+	for _, tld := range tlds {
+		normalised = append(normalised, normaliseDomain(tld))
+	}
+	tlds = normalised
+
+	if err := c.validateTLDsAvailable(ctx, tlds); err != nil {
+		return nil, err
+	}
+
+	terms, err := c.GetTermsAndConditions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &createAccessRequestBody{
+		TLDNames:  tlds,
+		Reason:    reason,
+		TCVersion: terms.Version,
+	}
+	var created []AccessRequest
+	if err := c.doJSON(ctx, http.MethodPost, "/requests/create", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create access request: %w", err)
+	}
+
+	return &AccessRequestResult{
+		TLDs:         tlds,
+		Reason:       reason,
+		TermsVersion: terms.Version,
+		Requests:     created,
+	}, nil
+}
+
+func (c *Client) validateTLDsAvailable(ctx context.Context, tlds []string) error {
+	available, err := c.ListTLDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list TLDs: %w", err)
+	}
+
+	statuses := make(map[string]TLDStatus, len(available))
+	for _, tld := range available {
+		statuses[normaliseDomain(tld.Name)] = tld.CurrentStatus
+	}
+
+	unavailable := make(map[string]TLDStatus)
+	for _, tld := range tlds {
+		if status := statuses[tld]; status != TLDStatusAvailable {
+			unavailable[tld] = status
+		}
+	}
+
+	if len(unavailable) > 0 {
+		return &TLDNotAvailableError{Statuses: unavailable}
+	}
+
+	return nil
+}
+
+// ListAccessRequests returns a single page of the access requests submitted by the account, narrowed down and
+// ordered according to the given filter. AccessRequests can be used to iterate over every page.
+func (c *Client) ListAccessRequests(ctx context.Context, filter AccessRequestFilter) (*AccessRequestPage, error) {
+	body := &listAccessRequestsBody{
+		Status: filter.Status.apiValue(),
+		Filter: filter.Filter,
+		Pagination: paginationOptions{
+			Size: filter.PageSize,
+			Page: filter.Page,
+		},
+		Sort: sortOptions{
+			Field:     filter.SortField,
+			Direction: filter.SortDirection,
+		},
+	}
+	if body.Pagination.Size <= 0 {
+		body.Pagination.Size = defaultAccessRequestsPageSize
+	}
+	if body.Sort.Field == "" {
+		body.Sort.Field = "created"
+	}
+	if body.Sort.Direction == "" {
+		body.Sort.Direction = "desc"
+	}
+
+	var resp listAccessRequestsResponse
+	if err := c.doJSON(ctx, http.MethodPost, "/requests/all", body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+
+	return &AccessRequestPage{
+		Requests: resp.Requests,
+		Page:     body.Pagination.Page,
+		PageSize: body.Pagination.Size,
+		Total:    resp.TotalRequests,
+	}, nil
+}
+
+const defaultAccessRequestsPageSize = 100
+
+// AccessRequestIterator iterates over every access request matching a filter, transparently fetching the pages as
+// they are needed.
+//
+//	it := client.AccessRequests(czds.AccessRequestFilter{})
+//	for it.Next(ctx) {
+//		fmt.Println(it.AccessRequest())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type AccessRequestIterator struct {
+	client   *Client
+	filter   AccessRequestFilter
+	requests []AccessRequest
+	current  AccessRequest
+	seen     int
+	total    int
+	started  bool
+	err      error
+}
+
+// AccessRequests returns an iterator over every access request matching the given filter, starting from the
+// filter's page.
+func (c *Client) AccessRequests(filter AccessRequestFilter) *AccessRequestIterator {
+	return &AccessRequestIterator{
+		client: c,
+		filter: filter,
+	}
+}
+
+// Next advances the iterator to the next access request, fetching the next page when required. It returns false
+// once every access request has been visited or an error occurs.
+func (it *AccessRequestIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.requests) == 0 {
+		if it.started && it.seen >= it.total {
+			return false
+		}
+
+		page, err := it.client.ListAccessRequests(ctx, it.filter)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		if !it.started {
+			it.started = true
+			it.seen = page.Page * page.PageSize
+		}
+		it.total = page.Total
+		it.requests = page.Requests
+		it.filter.Page++
+
+		if len(it.requests) == 0 {
+			return false
+		}
+	}
+
+	it.current = it.requests[0]
+	it.requests = it.requests[1:]
+	it.seen++
+
+	return true
+}
+
+// AccessRequest returns the access request the iterator currently points to.
+func (it *AccessRequestIterator) AccessRequest() AccessRequest {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *AccessRequestIterator) Err() error {
+	return it.err
+}
+
+// GetAccessRequest returns the full detail of the access request with the given ID, including the reason given
+// when submitting it and its history.
+func (c *Client) GetAccessRequest(ctx context.Context, requestID string) (*AccessRequestDetail, error) {
+	var detail AccessRequestDetail
+	if err := c.doJSON(ctx, http.MethodGet, "/requests/"+url.PathEscape(requestID), nil, &detail); err != nil {
+		return nil, fmt.Errorf("failed to get access request %s: %w", requestID, err)
+	}
+
+	return &detail, nil
+}
+
+// CancelAccessRequest cancels the access request with the given ID. Only submitted or pending requests can be
+// cancelled, otherwise a *RequestActionError is returned.
+func (c *Client) CancelAccessRequest(ctx context.Context, requestID string) error {
+	detail, err := c.GetAccessRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	if detail.Status != TLDStatusSubmitted && detail.Status != TLDStatusPending {
+		return &RequestActionError{RequestID: requestID, Action: "cancel", Status: detail.Status}
+	}
+
+	body := &cancelAccessRequestBody{
+		IntegrationID: requestID,
+		TLDName:       detail.TLD,
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/requests/cancel", body, nil); err != nil {
+		return fmt.Errorf("failed to cancel access request %s: %w", requestID, err)
+	}
+
+	return nil
+}
+
+// ExtendAccessRequest extends the access request with the given ID before it expires. Only approved requests can
+// be extended, otherwise a *RequestActionError is returned.
+func (c *Client) ExtendAccessRequest(ctx context.Context, requestID string) error {
+	detail, err := c.GetAccessRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	if detail.Status != TLDStatusApproved {
+		return &RequestActionError{RequestID: requestID, Action: "extend", Status: detail.Status}
+	}
+
+	endpoint := "/requests/extension/" + url.PathEscape(requestID)
+	if err := c.doJSON(ctx, http.MethodPost, endpoint, nil, nil); err != nil {
+		return fmt.Errorf("failed to extend access request %s: %w", requestID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package czds_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+const testTLDsResponse = `[{"tld":"dev","ulable":"dev","currentStatus":"available","sftp":false},
+{"tld":"tech","ulable":"tech","currentStatus":"approved","sftp":false},
+{"tld":"com","ulable":"com","currentStatus":"available","sftp":false}]`
+
+func TestRequestAccess(t *testing.T) {
+	testCreatedRequests := []czds.AccessRequest{
+		{RequestID: "id-1", TLD: "dev", Status: czds.TLDStatusSubmitted},
+		{RequestID: "id-2", TLD: "com", Status: czds.TLDStatusSubmitted},
+	}
+
+	for name, tc := range map[string]struct {
+		tlds             []string
+		reason           string
+		createResponse   string
+		expectedTLDNames []string
+		expectedResult   *czds.AccessRequestResult
+		errAssert        assert.ErrorAssertionFunc
+	}{
+		"Success": {
+			tlds:   []string{"dev", "com"},
+			reason: "research",
+			createResponse: `[{"requestId":"id-1","tld":"dev","status":"Submitted"},
+{"requestId":"id-2","tld":"com","status":"Submitted"}]`,
+			expectedTLDNames: []string{"dev", "com"},
+			expectedResult: &czds.AccessRequestResult{
+				TLDs:         []string{"dev", "com"},
+				Reason:       "research",
+				TermsVersion: "7",
+				Requests:     testCreatedRequests,
+			},
+			errAssert: assert.NoError,
+		},
+		"Success_NormalisesTLDNames": {
+			tlds:   []string{"DEV", "Com."},
+			reason: "research",
+			createResponse: `[{"requestId":"id-1","tld":"dev","status":"Submitted"},
+{"requestId":"id-2","tld":"com","status":"Submitted"}]`,
+			expectedTLDNames: []string{"dev", "com"},
+			expectedResult: &czds.AccessRequestResult{
+				TLDs:         []string{"dev", "com"},
+				Reason:       "research",
+				TermsVersion: "7",
+				Requests:     testCreatedRequests,
+			},
+			errAssert: assert.NoError,
+		},
+		"Fail_UnexpectedResponse": {
+			tlds:             []string{"dev"},
+			reason:           "research",
+			createResponse:   `{"requestId":"id-1","tld":"dev","status":"Submitted"}`,
+			expectedTLDNames: []string{"dev"},
+			errAssert:        assert.Error,
+		},
+		"Fail_EmptyResponse": {
+			tlds:             []string{"dev"},
+			reason:           "research",
+			expectedTLDNames: []string{"dev"},
+			errAssert:        assert.Error,
+		},
+		"Fail_TLDNotAvailable": {
+			tlds:   []string{"dev", "tech", "xyz"},
+			reason: "research",
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var notAvailableErr *czds.TLDNotAvailableError
+				return assert.ErrorAs(t, err, &notAvailableErr) &&
+					assert.Equal(t, map[string]czds.TLDStatus{"tech": czds.TLDStatusApproved, "xyz": ""}, notAvailableErr.Statuses)
+			},
+		},
+		"Fail_MissingReason": {
+			tlds:      []string{"dev"},
+			errAssert: assert.Error,
+		},
+		"Fail_MissingTLDs": {
+			reason:    "research",
+			errAssert: assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /tlds", func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(testTLDsResponse))
+				require.NoError(t, err)
+			})
+			mux.HandleFunc("GET /terms/condition", func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(`{"version":"7","content":"terms"}`))
+				require.NoError(t, err)
+			})
+			mux.HandleFunc("POST /requests/create", func(w http.ResponseWriter, r *http.Request) {
+				var reqBody struct {
+					AllTLDs   bool     `json:"allTlds"`
+					TLDNames  []string `json:"tldNames"`
+					Reason    string   `json:"reason"`
+					TCVersion string   `json:"tcVersion"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+				require.False(t, reqBody.AllTLDs)
+				require.Equal(t, tc.expectedTLDNames, reqBody.TLDNames)
+				require.Equal(t, tc.reason, reqBody.Reason)
+				require.Equal(t, "7", reqBody.TCVersion)
+
+				_, err := w.Write([]byte(tc.createResponse))
+				require.NoError(t, err)
+			})
+			mockCZDSAPI := httptest.NewServer(mux)
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			result, err := client.RequestAccess(context.Background(), tc.tlds, tc.reason)
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestAccessRequests(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	pages := []string{
+		`{"requests":[
+{"requestId":"id-1","tld":"dev","ulabel":"dev","status":"Approved","created":"2024-01-02T03:04:05.000+0000",
+"last_updated":"2024-01-03T03:04:05Z","expired":"2025-01-02T03:04:05Z","sftp":false},
+{"requestId":"id-2","tld":"tech","ulabel":"tech","status":"Pending","created":1704164645000,
+"last_updated":null,"expired":null,"sftp":true}],"totalRequests":3}`,
+		`{"requests":[{"requestId":"id-3","tld":"com","ulabel":"com","status":"Denied","created":"2024-01-02"}],
+"totalRequests":3}`,
+	}
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/requests/all", r.URL.Path)
+
+		var reqBody struct {
+			Status     string `json:"status"`
+			Filter     string `json:"filter"`
+			Pagination struct {
+				Size int `json:"size"`
+				Page int `json:"page"`
+			} `json:"pagination"`
+			Sort struct {
+				Field     string `json:"field"`
+				Direction string `json:"direction"`
+			} `json:"sort"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.Equal(t, 2, reqBody.Pagination.Size)
+		require.Equal(t, "created", reqBody.Sort.Field)
+		require.Equal(t, "desc", reqBody.Sort.Direction)
+		require.Less(t, reqBody.Pagination.Page, len(pages))
+
+		_, err := w.Write([]byte(pages[reqBody.Pagination.Page]))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	var requests []czds.AccessRequest
+	it := client.AccessRequests(czds.AccessRequestFilter{PageSize: 2})
+	for it.Next(context.Background()) {
+		requests = append(requests, it.AccessRequest())
+	}
+	require.NoError(t, it.Err())
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "id-1", requests[0].RequestID)
+	assert.Equal(t, "dev", requests[0].TLD)
+	assert.Equal(t, czds.TLDStatusApproved, requests[0].Status)
+	assert.True(t, requests[0].Created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.True(t, requests[0].LastUpdated.Equal(time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC)))
+	assert.True(t, requests[0].Expired.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.False(t, requests[0].SFTP)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), requests[1].Created)
+	assert.True(t, requests[1].Expired.IsZero())
+	assert.True(t, requests[1].SFTP)
+	assert.Equal(t, "id-3", requests[2].RequestID)
+}
+
+func TestGetAccessRequest(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/requests/id-1", r.URL.Path)
+
+		_, err := w.Write([]byte(`{"requestId":"id-1","tld":{"tld":"dev","ulable":"dev"},"status":"Approved",
+"reason":"research","tcVersion":"7","created":"2024-01-02T03:04:05Z",
+"history":[{"timestamp":"2024-01-02T03:04:05Z","action":"Request submitted","comment":null}]}`))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	detail, err := client.GetAccessRequest(context.Background(), "id-1")
+	require.NoError(t, err)
+	assert.Equal(t, &czds.AccessRequestDetail{
+		AccessRequest: czds.AccessRequest{
+			RequestID: "id-1",
+			TLD:       "dev",
+			ULabel:    "dev",
+			Status:    czds.TLDStatusApproved,
+			Created:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Reason:       "research",
+		TermsVersion: "7",
+		History: []czds.AccessRequestEvent{
+			{Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Action: "Request submitted"},
+		},
+	}, detail)
+}
+
+func TestAccessRequestActions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status       string
+		action       func(client *czds.Client) error
+		expectedPath string
+		errAssert    assert.ErrorAssertionFunc
+	}{
+		"Success_CancelPending": {
+			status: "Pending",
+			action: func(client *czds.Client) error {
+				return client.CancelAccessRequest(context.Background(), "id-1")
+			},
+			expectedPath: "/requests/cancel",
+			errAssert:    assert.NoError,
+		},
+		"Success_ExtendApproved": {
+			status: "Approved",
+			action: func(client *czds.Client) error {
+				return client.ExtendAccessRequest(context.Background(), "id-1")
+			},
+			expectedPath: "/requests/extension/id-1",
+			errAssert:    assert.NoError,
+		},
+		"Fail_CancelApproved": {
+			status: "Approved",
+			action: func(client *czds.Client) error {
+				return client.CancelAccessRequest(context.Background(), "id-1")
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var actionErr *czds.RequestActionError
+				return assert.ErrorIs(t, err, czds.ErrActionNotAllowed) &&
+					assert.ErrorAs(t, err, &actionErr) &&
+					assert.Equal(t, "cancel", actionErr.Action) &&
+					assert.Equal(t, czds.TLDStatusApproved, actionErr.Status)
+			},
+		},
+		"Fail_ExtendDenied": {
+			status: "Denied",
+			action: func(client *czds.Client) error {
+				return client.ExtendAccessRequest(context.Background(), "id-1")
+			},
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrActionNotAllowed)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			var actionCalled bool
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /requests/id-1", func(w http.ResponseWriter, r *http.Request) {
+				_, err := fmt.Fprintf(w, `{"requestId":"id-1","tld":"dev","status":%q}`, tc.status)
+				require.NoError(t, err)
+			})
+			mux.HandleFunc("POST /requests/", func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.expectedPath, r.URL.Path)
+				actionCalled = true
+
+				if r.URL.Path == "/requests/cancel" {
+					var reqBody struct {
+						IntegrationID string `json:"integrationId"`
+						TLDName       string `json:"tldName"`
+					}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+					require.Equal(t, "id-1", reqBody.IntegrationID)
+					require.Equal(t, "dev", reqBody.TLDName)
+				}
+			})
+			mockCZDSAPI := httptest.NewServer(mux)
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			tc.errAssert(t, tc.action(client))
+			assert.Equal(t, tc.expectedPath != "", actionCalled)
+		})
+	}
+}
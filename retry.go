@@ -0,0 +1,347 @@
+package czds
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures how requests to CZDS are retried when they fail with a transient error, such as a reset
+// connection, or a retryable HTTP status. Delays between attempts grow exponentially, unless the server asks for a
+// specific delay with a Retry-After header. Zero fields take the values of DefaultRetryPolicy, except for Jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Resuming an interrupted download
+	// counts as an attempt.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. When the server asks to wait longer than this with a Retry-After
+	// header, its response is returned instead of being retried.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each attempt.
+	Multiplier float64
+	// Jitter is the fraction of each delay, between 0 and 1, which is randomised, so concurrent clients don't
+	// retry in lockstep. Zero disables jitter.
+	Jitter float64
+	// RetryableStatuses are the HTTP statuses retried.
+	RetryableStatuses []int
+	// RetryableMethods are the HTTP methods retried. Only idempotent methods should be listed, as a request which
+	// failed may still have been processed by the server.
+	RetryableMethods []string
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts of GET and HEAD requests, starting with a one-second
+// delay which doubles after each attempt up to 30 seconds, randomised by up to half. HTTP 429 and the 5xx statuses
+// signalling a temporary failure are retried.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableMethods: []string{http.MethodGet, http.MethodHead},
+	}
+}
+
+// withDefaults returns a copy of the policy with its zero fields set to the default values.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.RetryableStatuses == nil {
+		p.RetryableStatuses = defaults.RetryableStatuses
+	}
+	if p.RetryableMethods == nil {
+		p.RetryableMethods = defaults.RetryableMethods
+	}
+	return p
+}
+
+// backoff returns the delay before the attempt following the given one, counting attempts from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	delay -= delay * p.Jitter * rand.Float64()
+
+	return time.Duration(delay)
+}
+
+// retryDelay reports whether the given attempt, which returned resp or failed with err, should be retried and how
+// long to wait before doing so.
+func (p RetryPolicy) retryDelay(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if err != nil {
+		return p.backoff(attempt), isTransientError(err)
+	}
+
+	if !slices.Contains(p.RetryableStatuses, resp.StatusCode) {
+		return 0, false
+	}
+
+	if wait := parseRetryAfter(resp.Header.Get("Retry-After")); wait > 0 {
+		return wait, wait <= p.MaxBackoff
+	}
+
+	return p.backoff(attempt), true
+}
+
+// isTransientError reports whether err is a connection-level failure of the CZDS request worth retrying, such as a
+// connection which failed to be established or broke while in use, a timeout or a response cut short. Failures
+// which would recur on every attempt, such as an untrusted certificate, an unsupported URL or a host which doesn't
+// exist, aren't, and neither are context errors or failures to obtain a JWT.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || isPermanentError(err) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "read" || opErr.Op == "write") {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// isPermanentError reports whether err is a failure which retrying can't fix, such as a failed certificate check, a
+// host which doesn't exist or a failure to obtain a JWT, which is left to the authentication rate limit.
+func isPermanentError(err error) bool {
+	var (
+		refreshErr     *tokenRefreshError
+		certErr        *tls.CertificateVerificationError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		certInvalidErr x509.CertificateInvalidError
+		dnsErr         *net.DNSError
+	)
+
+	return errors.As(err, &refreshErr) ||
+		errors.As(err, &certErr) ||
+		errors.As(err, &unknownAuthErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certInvalidErr) ||
+		(errors.As(err, &dnsErr) && dnsErr.IsNotFound)
+}
+
+// retryTransport retries the requests sent through the next transport according to the retry policy, and resumes
+// interrupted downloads with range requests.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !slices.Contains(t.policy.RetryableMethods, req.Method) {
+		return t.next.RoundTrip(req)
+	}
+
+	getBody, err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.send(req, getBody)
+
+		delay, retry := t.policy.retryDelay(attempt, resp, err)
+		if !retry || req.Context().Err() != nil {
+			return t.resumable(req, resp, attempt), err
+		}
+
+		if resp != nil {
+			discardBody(resp)
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends a copy of the request with a fresh copy of its body, leaving the original request untouched.
+func (t *retryTransport) send(req *http.Request, getBody bodyGetter) (*http.Response, error) {
+	if getBody == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := getBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+
+	return t.next.RoundTrip(attemptReq)
+}
+
+// resumable wraps the body of a successful download, so it is resumed with a range request if the connection
+// breaks while it is being read. Responses which can't be resumed are returned as they are.
+func (t *retryTransport) resumable(req *http.Request, resp *http.Response, attempt int) *http.Response {
+	if resp == nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK || resp.Uncompressed ||
+		resp.Header.Get("Accept-Ranges") == "none" || attempt >= t.policy.MaxAttempts {
+		return resp
+	}
+
+	resp.Body = &resumableBody{
+		transport: t,
+		req:       req,
+		body:      resp.Body,
+		validator: rangeValidator(resp.Header),
+		size:      resp.ContentLength,
+		attempt:   attempt,
+	}
+	return resp
+}
+
+// rangeValidator returns the strong ETag or the Last-Modified time of a response, which a range request must match
+// for the resumed response to be a continuation of the same content.
+func rangeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// resumableBody is a response body which resumes reading from where it broke off with a range request, should the
+// connection fail while it is being read.
+type resumableBody struct {
+	transport *retryTransport
+	req       *http.Request
+	body      io.ReadCloser
+	validator string
+	size      int64
+	offset    int64
+	attempt   int
+}
+
+func (b *resumableBody) Read(p []byte) (int, error) {
+	for {
+		n, err := b.body.Read(p)
+		b.offset += int64(n)
+
+		if err == nil || errors.Is(err, io.EOF) || !isTransientError(err) || !b.resume() {
+			return n, err
+		}
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (b *resumableBody) Close() error {
+	return b.body.Close()
+}
+
+// resume replaces the broken body with the remainder of the content, requested with a range request. It reports
+// false once the attempts are exhausted or the server can't resume the download.
+func (b *resumableBody) resume() bool {
+	policy := b.transport.policy
+	for b.attempt < policy.MaxAttempts {
+		if err := sleep(b.req.Context(), policy.backoff(b.attempt)); err != nil {
+			return false
+		}
+		b.attempt++
+
+		resp, err := b.transport.next.RoundTrip(b.rangeRequest())
+		if err != nil {
+			if isTransientError(err) {
+				continue
+			}
+			return false
+		}
+
+		if !b.continues(resp) {
+			discardBody(resp)
+			return false
+		}
+
+		_ = b.body.Close()
+		b.body = resp.Body
+		return true
+	}
+
+	return false
+}
+
+func (b *resumableBody) rangeRequest() *http.Request {
+	req := b.req.Clone(b.req.Context())
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", b.offset))
+	if b.validator != "" {
+		req.Header.Set("If-Range", b.validator)
+	}
+	return req
+}
+
+// continues reports whether the response to a range request holds the remainder of the original content.
+func (b *resumableBody) continues(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+
+	// Content-Range has the form "bytes first-last/size", where the size may be unknown and given as "*".
+	contentRange, found := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !found {
+		return false
+	}
+	span, size, _ := strings.Cut(contentRange, "/")
+	first, _, _ := strings.Cut(span, "-")
+
+	if start, err := strconv.ParseInt(first, 10, 64); err != nil || start != b.offset {
+		return false
+	}
+
+	return b.size < 0 || size == strconv.FormatInt(b.size, 10)
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
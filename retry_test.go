@@ -0,0 +1,261 @@
+package czds_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	testPolicy := czds.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Second}
+
+	for name, tc := range map[string]struct {
+		policy        *czds.RetryPolicy
+		responses     []int
+		retryAfter    string
+		expectedCalls int32
+		errAssert     assert.ErrorAssertionFunc
+	}{
+		"Success_RetriesServerError": {
+			policy:        &testPolicy,
+			responses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedCalls: 3,
+			errAssert:     assert.NoError,
+		},
+		"Success_HonoursRetryAfter": {
+			policy:        &testPolicy,
+			responses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:    "1",
+			expectedCalls: 2,
+			errAssert:     assert.NoError,
+		},
+		"Fail_RetryAfterExceedsMaxBackoff": {
+			policy:        &testPolicy,
+			responses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:    "60",
+			expectedCalls: 1,
+			errAssert:     assert.Error,
+		},
+		"Fail_MaxAttemptsExhausted": {
+			policy:        &testPolicy,
+			responses:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedCalls: 3,
+			errAssert:     assert.Error,
+		},
+		"Fail_NonRetryableStatus": {
+			policy:        &testPolicy,
+			responses:     []int{http.StatusNotFound, http.StatusOK},
+			expectedCalls: 1,
+			errAssert:     assert.Error,
+		},
+		"Fail_NoRetryPolicy": {
+			responses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedCalls: 1,
+			errAssert:     assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			var calls atomic.Int32
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tc.responses[calls.Add(1)-1]
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, err := w.Write([]byte(testZoneFile))
+					require.NoError(t, err)
+				}
+			}))
+			defer mockCZDSAPI.Close()
+
+			opts := []czds.ClientOption{
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL),
+			}
+			if tc.policy != nil {
+				opts = append(opts, czds.RetryPolicyOpt(*tc.policy))
+			}
+			client := czds.NewClient(testEmail, testPassword, opts...)
+
+			_, err := client.GetZoneFile(context.Background(), "com")
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryPermanentErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		czdsAPI func(t *testing.T) (baseURL string, close func())
+	}{
+		"UntrustedCertificate": {
+			czdsAPI: func(t *testing.T) (string, func()) {
+				server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					t.Error("the TLS handshake should fail")
+				}))
+				server.Config.ErrorLog = log.New(io.Discard, "", 0)
+				return server.URL, server.Close
+			},
+		},
+		"UnsupportedScheme": {
+			czdsAPI: func(t *testing.T) (string, func()) {
+				return "ftp://czds.example", func() {}
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			czdsAPIBaseURL, closeCZDSAPI := tc.czdsAPI(t)
+			defer closeCZDSAPI()
+
+			var attempts atomic.Int32
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if strings.HasPrefix(czdsAPIBaseURL, req.URL.Scheme+"://"+req.URL.Host) {
+					attempts.Add(1)
+				}
+				return http.DefaultTransport.RoundTrip(req)
+			})
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.HTTPTransport(transport),
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(czdsAPIBaseURL),
+				czds.RetryPolicyOpt(czds.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond}))
+
+			_, err := client.ListTLDs(context.Background())
+			assert.Error(t, err)
+			assert.Equal(t, int32(1), attempts.Load())
+		})
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryAuthenticationFailures(t *testing.T) {
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// An empty body fails to decode with io.EOF, which must not be mistaken for a broken connection.
+		authCalls.Add(1)
+	}))
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("CZDS should not be called without a JWT")
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL),
+		czds.RetryPolicyOpt(czds.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond}))
+
+	_, err := client.ListTLDs(context.Background())
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, int32(1), authCalls.Load())
+}
+
+func TestRetryPolicy_DoesNotRetryPOST(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	var calls atomic.Int32
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL),
+		czds.RetryPolicyOpt(czds.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	_, err := client.ListAccessRequests(context.Background(), czds.AccessRequestFilter{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryPolicy_ResumesInterruptedDownload(t *testing.T) {
+	for name, tc := range map[string]struct {
+		etag           string
+		expectedRanges []string
+		errAssert      assert.ErrorAssertionFunc
+	}{
+		"Success_Resumed": {
+			etag:           `"v1"`,
+			expectedRanges: []string{"", fmt.Sprintf("bytes=%d-", len(testZoneFile)/2)},
+			errAssert:      assert.NoError,
+		},
+		"Fail_ContentChanged": {
+			etag:           `"v2"`,
+			expectedRanges: []string{"", fmt.Sprintf("bytes=%d-", len(testZoneFile)/2)},
+			errAssert:      assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			var ranges []string
+			mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				if len(ranges) > 1 {
+					w.Header().Set("ETag", tc.etag)
+					http.ServeContent(w, r, "", time.Time{}, strings.NewReader(testZoneFile))
+					return
+				}
+
+				// Send half of the zone file, then break the connection.
+				conn, buf, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				defer conn.Close()
+
+				_, err = fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\nETag: \"v1\"\r\n"+
+					"Accept-Ranges: bytes\r\n\r\n%s", len(testZoneFile), testZoneFile[:len(testZoneFile)/2])
+				require.NoError(t, err)
+				require.NoError(t, buf.Flush())
+			}))
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL),
+				czds.RetryPolicyOpt(czds.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+			records, err := client.GetZoneFile(context.Background(), "com")
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedRanges, ranges)
+
+			if err == nil {
+				expected, err := czds.ParseZone(strings.NewReader(testZoneFile))
+				require.NoError(t, err)
+				assert.Equal(t, expected, records)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// TokenInfo describes the JWT the client authenticates CZDS requests with, as read from its claims. The claims
+// aren't verified, as the signing key is only known to ICANN.
+type TokenInfo struct {
+	// Value is the raw JWT.
+	Value string
+	// Subject is the sub claim, identifying the account the JWT was issued for.
+	Subject string
+	// IssuedAt is the iat claim, or the zero time if the JWT has none.
+	IssuedAt time.Time
+	// ExpiresAt is the time the JWT expires at, or the zero time if it isn't known.
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the JWT has expired. A JWT without a known expiry time is never considered expired.
+func (i TokenInfo) Expired() bool {
+	return !i.ExpiresAt.IsZero() && !time.Now().Before(i.ExpiresAt)
+}
+
+// Token returns details of the JWT currently held in the token store, which may have expired. ErrNoToken is
+// returned when no JWT is stored. It doesn't authenticate, use Login to obtain a new JWT.
+func (c *Client) Token(ctx context.Context) (TokenInfo, error) {
+	stored, err := c.auth.tokenStore.Get(ctx)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to get JWT from store: %w", err)
+	}
+	if stored.Value == "" {
+		return TokenInfo{}, ErrNoToken
+	}
+
+	return newTokenInfo(stored), nil
+}
+
+// Login authenticates eagerly and returns details of the new JWT, which replaces the stored one once
+// authentication succeeds. It can be used to validate the credentials at startup or to force a refresh. Should
+// authentication fail, the stored JWT is kept. Authentication errors are reported as *AuthError.
+func (c *Client) Login(ctx context.Context) (TokenInfo, error) {
+	token, err := c.auth.refreshToken(ctx, forceRefresh, "")
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	return newTokenInfo(Token{Value: token}), nil
+}
+
+// Logout removes the JWT from the token store while holding the TokenLocker, if any. As the store may be shared,
+// every client using it authenticates again on its next request.
+func (c *Client) Logout(ctx context.Context) error {
+	if c.auth.locker != nil {
+		unlock, err := c.auth.locker.Lock(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to acquire token lock: %w", err)
+		}
+		defer unlock()
+	}
+
+	if err := c.auth.tokenStore.Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete JWT: %w", err)
+	}
+	return nil
+}
+
+// newTokenInfo reads the claims of the given token. The expiry time of the token is preferred over the exp claim.
+func newTokenInfo(token Token) TokenInfo {
+	info := TokenInfo{Value: token.Value, ExpiresAt: token.ExpiresAt}
+
+	parsedToken, _, err := new(jwt.Parser).ParseUnverified(token.Value, jwt.MapClaims{})
+	if err != nil {
+		return info
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return info
+	}
+
+	info.Subject, _ = claims["sub"].(string)
+	if iat, ok := claims["iat"].(float64); ok {
+		info.IssuedAt = time.Unix(int64(iat), 0)
+	}
+	if exp, ok := claims["exp"].(float64); ok && info.ExpiresAt.IsZero() {
+		info.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+
+	return info
+}
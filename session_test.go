@@ -0,0 +1,110 @@
+package czds_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestClient_LoginTokenLogout(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	tokenStore := &czds.InMemoryTokenStore{}
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.TokenStoreOpt(tokenStore))
+
+	_, err := client.Token(context.Background())
+	require.ErrorIs(t, err, czds.ErrNoToken)
+
+	expected := czds.TokenInfo{
+		Value:     testGoodToken,
+		Subject:   "1234567890",
+		IssuedAt:  time.Unix(1516239022, 0),
+		ExpiresAt: time.Unix(88888888888, 0),
+	}
+
+	info, err := client.Login(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, info)
+	assert.False(t, info.Expired())
+
+	info, err = client.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, info)
+
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Empty(t, tokenStore.Get(context.Background()))
+
+	_, err = client.Token(context.Background())
+	assert.ErrorIs(t, err, czds.ErrNoToken)
+}
+
+func TestClient_Login(t *testing.T) {
+	storedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		statusCode          int
+		expectedStoredToken string
+		errAssert           assert.ErrorAssertionFunc
+	}{
+		"Success": {
+			statusCode:          http.StatusOK,
+			expectedStoredToken: testGoodToken,
+			errAssert:           assert.NoError,
+		},
+		"Fail_InvalidCredentials_KeepsStoredToken": {
+			statusCode:          http.StatusUnauthorized,
+			expectedStoredToken: storedToken,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrInvalidCredentials)
+			},
+		},
+		"Fail_ServerFailure_KeepsStoredToken": {
+			statusCode:          http.StatusServiceUnavailable,
+			expectedStoredToken: storedToken,
+			errAssert: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, czds.ErrAuthServerFailure)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var authCalls atomic.Int32
+			mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls.Add(1)
+				w.WriteHeader(tc.statusCode)
+				_, err := w.Write([]byte(`{"accessToken":"` + testGoodToken + `","message":"Authentication Successful"}`))
+				require.NoError(t, err)
+			}))
+			defer mockAccountsAPI.Close()
+
+			tokenStore := &czds.InMemoryTokenStore{}
+			require.NoError(t, tokenStore.Save(context.Background(), storedToken))
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.TokenStoreOpt(tokenStore))
+
+			_, err := client.Login(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, int32(1), authCalls.Load(), "login should authenticate even when a valid JWT is stored")
+			assert.Equal(t, tc.expectedStoredToken, tokenStore.Get(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GetTermsAndConditions returns the current version of the CZDS terms and conditions, which are referenced and
+// accepted when requesting access to zone files. The version can be compared over time to detect changes.
+func (c *Client) GetTermsAndConditions(ctx context.Context) (*TermsAndConditions, error) {
+	var terms TermsAndConditions
+	if err := c.doJSON(ctx, http.MethodGet, "/terms/condition", nil, &terms); err != nil {
+		return nil, fmt.Errorf("failed to get terms and conditions: %w", err)
+	}
+
+	return &terms, nil
+}
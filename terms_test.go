@@ -0,0 +1,69 @@
+package czds_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestGetTermsAndConditions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		setupCZDSAPIMock func() *httptest.Server
+		expectedTerms    *czds.TermsAndConditions
+		errAssert        assert.ErrorAssertionFunc
+	}{
+		"Success": {
+			setupCZDSAPIMock: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, http.MethodGet, r.Method)
+					require.Equal(t, "/terms/condition", r.URL.Path)
+
+					_, err := w.Write([]byte(`{"version":"7","content":"<p>Terms</p>",
+"contentUrl":"https://czds.icann.org/terms/7","created":"2024-01-02T03:04:05Z"}`))
+					require.NoError(t, err)
+				}))
+			},
+			expectedTerms: &czds.TermsAndConditions{
+				Version:    "7",
+				Published:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Content:    "<p>Terms</p>",
+				ContentURL: "https://czds.icann.org/terms/7",
+			},
+			errAssert: assert.NoError,
+		},
+		"Fail_APIReturnsHTTP500": {
+			setupCZDSAPIMock: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}))
+			},
+			errAssert: assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAccountsAPI := newTestAccountsAPI(t)
+			defer mockAccountsAPI.Close()
+
+			mockCZDSAPI := tc.setupCZDSAPIMock()
+			defer mockCZDSAPI.Close()
+
+			client := czds.NewClient(testEmail, testPassword,
+				czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+				czds.APIBaseURL(mockCZDSAPI.URL))
+
+			terms, err := client.GetTermsAndConditions(context.Background())
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedTerms, terms)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package czds
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// TLDStatus is the status of a TLD for the account, or of an access request submitted for it.
+type TLDStatus string
+
+const (
+	// TLDStatusAvailable means access to the TLD can be requested.
+	TLDStatusAvailable TLDStatus = "available"
+	// TLDStatusSubmitted means access was requested but the request hasn't been processed yet.
+	TLDStatusSubmitted TLDStatus = "submitted"
+	// TLDStatusPending means the access request is awaiting approval from the registry operator.
+	TLDStatusPending TLDStatus = "pending"
+	// TLDStatusApproved means the zone file can be downloaded.
+	TLDStatusApproved TLDStatus = "approved"
+	// TLDStatusDenied means the registry operator denied the access request.
+	TLDStatusDenied TLDStatus = "denied"
+	// TLDStatusRevoked means the registry operator revoked a previously approved access.
+	TLDStatusRevoked TLDStatus = "revoked"
+	// TLDStatusExpired means a previously approved access has expired.
+	TLDStatusExpired TLDStatus = "expired"
+	// TLDStatusCancelled means the access request was cancelled before being processed.
+	TLDStatusCancelled TLDStatus = "cancelled"
+)
+
+var knownTLDStatuses = []TLDStatus{
+	TLDStatusAvailable,
+	TLDStatusSubmitted,
+	TLDStatusPending,
+	TLDStatusApproved,
+	TLDStatusDenied,
+	TLDStatusRevoked,
+	TLDStatusExpired,
+	TLDStatusCancelled,
+}
+
+// IsKnown reports whether the status is one of the statuses known to this client.
+func (s TLDStatus) IsKnown() bool {
+	return slices.Contains(knownTLDStatuses, s)
+}
+
+// UnmarshalJSON decodes a status case-insensitively. Unknown statuses are kept as they are, lower-cased, while
+// values which aren't strings decode to an empty status rather than failing the whole response.
+func (s *TLDStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if json.Unmarshal(data, &value) != nil {
+		value = ""
+	}
+
+	*s = TLDStatus(strings.ToLower(value))
+	return nil
+}
+
+// apiValue returns the status as expected by the CZDS API filters, e.g. "Approved".
+func (s TLDStatus) apiValue() string {
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(string(s[:1])) + string(s[1:])
+}
+
+// ListApprovedTLDs returns the TLDs whose zone files can be downloaded by the account.
+func (c *Client) ListApprovedTLDs(ctx context.Context) ([]TLD, error) {
+	return c.ListTLDsByStatus(ctx, TLDStatusApproved)
+}
+
+// ListTLDsByStatus returns the TLDs in any of the given statuses.
+func (c *Client) ListTLDsByStatus(ctx context.Context, statuses ...TLDStatus) ([]TLD, error) {
+	tlds, err := c.ListTLDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []TLD
+	for _, tld := range tlds {
+		if slices.Contains(statuses, tld.CurrentStatus) {
+			filtered = append(filtered, tld)
+		}
+	}
+
+	return filtered, nil
+}
+
+// GroupTLDsByStatus groups the given TLDs by their current status.
+func GroupTLDsByStatus(tlds []TLD) map[TLDStatus][]TLD {
+	groups := make(map[TLDStatus][]TLD)
+	for _, tld := range tlds {
+		groups[tld.CurrentStatus] = append(groups[tld.CurrentStatus], tld)
+	}
+	return groups
+}
@@ -0,0 +1,75 @@
+package czds_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestTLDStatus_UnmarshalJSON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data           string
+		expectedStatus czds.TLDStatus
+		expectedKnown  bool
+	}{
+		"Success_Known":           {data: `"approved"`, expectedStatus: czds.TLDStatusApproved, expectedKnown: true},
+		"Success_CaseInsensitive": {data: `"Pending"`, expectedStatus: czds.TLDStatusPending, expectedKnown: true},
+		"Success_Unknown":         {data: `"suspended"`, expectedStatus: "suspended"},
+		"Success_NotAString":      {data: `42`, expectedStatus: ""},
+		"Success_Null":            {data: `null`, expectedStatus: ""},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var status czds.TLDStatus
+			require.NoError(t, json.Unmarshal([]byte(tc.data), &status))
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedKnown, status.IsKnown())
+		})
+	}
+}
+
+func TestListApprovedTLDs(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/tlds", r.URL.Path)
+
+		_, err := w.Write([]byte(testTLDsResponse))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.APIBaseURL(mockCZDSAPI.URL))
+
+	tlds, err := client.ListApprovedTLDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []czds.TLD{
+		{Name: "tech", Ulable: "tech", CurrentStatus: czds.TLDStatusApproved},
+	}, tlds)
+}
+
+func TestGroupTLDsByStatus(t *testing.T) {
+	tlds := []czds.TLD{
+		{Name: "dev", CurrentStatus: czds.TLDStatusApproved},
+		{Name: "tech", CurrentStatus: czds.TLDStatusPending},
+		{Name: "com", CurrentStatus: czds.TLDStatusApproved},
+	}
+
+	assert.Equal(t, map[czds.TLDStatus][]czds.TLD{
+		czds.TLDStatusApproved: {tlds[0], tlds[2]},
+		czds.TLDStatusPending:  {tlds[1]},
+	}, czds.GroupTLDsByStatus(tlds))
+}
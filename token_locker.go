@@ -0,0 +1,46 @@
+package czds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TokenLocker coordinates JWT refreshes between processes sharing a token store. While a process holds the lock
+// it re-reads the store and only authenticates if no other process has stored a valid JWT in the meantime, so a
+// fleet of workers sharing a store fetches a single JWT when it expires instead of one each.
+type TokenLocker interface {
+	// Lock blocks until the lock is acquired or the context is done. The returned function releases the lock.
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// TokenLockerFunc is an adapter to allow the use of ordinary functions as a TokenLocker.
+type TokenLockerFunc func(ctx context.Context) (func(), error)
+
+// Lock calls f(ctx).
+func (f TokenLockerFunc) Lock(ctx context.Context) (func(), error) {
+	return f(ctx)
+}
+
+// FileTokenLocker implements TokenLocker with an advisory lock on a file, coordinating the processes on the same
+// host, such as those sharing a FileTokenStore. On platforms without advisory file locks, i.e. other than unix and
+// Windows, Lock fails with ErrFileLockUnsupported rather than pretending to hold the lock.
+type FileTokenLocker struct {
+	path string
+}
+
+// NewFileTokenLocker returns a FileTokenLocker locking the file at the given path, which is created if needed. The
+// path must differ from the lock file of any FileTokenStore, as the store locks it while the refresh lock is held.
+func NewFileTokenLocker(path string) *FileTokenLocker {
+	return &FileTokenLocker{path: path}
+}
+
+// Lock acquires an exclusive lock on the file, waiting for it until the context is done.
+func (l *FileTokenLocker) Lock(ctx context.Context) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create token lock directory: %w", err)
+	}
+
+	return lockFile(ctx, l.path, true)
+}
@@ -0,0 +1,83 @@
+package czds_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestTokenLocker_CoordinatesRefreshAcrossClients(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("advisory file locks are not supported on Windows")
+	}
+
+	var authCalls atomic.Int32
+	mockAccountsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		testResponse := fmt.Sprintf(`{"accessToken":%q,"message":"Authentication Successful"}`, testGoodToken)
+		_, err := w.Write([]byte(testResponse))
+		require.NoError(t, err)
+	}))
+	defer mockAccountsAPI.Close()
+
+	mockCZDSAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer "+testGoodToken, r.Header.Get("Authorization"))
+
+		_, err := w.Write([]byte("test-1.com.\t10800\tin\tns\ttest-dns-1.com."))
+		require.NoError(t, err)
+	}))
+	defer mockCZDSAPI.Close()
+
+	dir := t.TempDir()
+
+	// Each client stands in for a separate worker process, sharing only the token file and the refresh lock file.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		client := czds.NewClient(testEmail, testPassword,
+			czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+			czds.APIBaseURL(mockCZDSAPI.URL),
+			czds.TokenStoreOpt(czds.NewFileTokenStore(filepath.Join(dir, "token.json"))),
+			czds.TokenLockerOpt(czds.NewFileTokenLocker(filepath.Join(dir, "refresh.lock"))))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := client.GetZoneFile(context.Background(), "com")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), authCalls.Load())
+}
+
+func TestTokenLocker_LockError(t *testing.T) {
+	mockAccountsAPI := newTestAccountsAPI(t)
+	defer mockAccountsAPI.Close()
+
+	locker := czds.TokenLockerFunc(func(ctx context.Context) (func(), error) {
+		return nil, fmt.Errorf("lock backend unavailable")
+	})
+
+	client := czds.NewClient(testEmail, testPassword,
+		czds.ICANNAccountsAPIBaseURL(mockAccountsAPI.URL),
+		czds.TokenLockerOpt(locker))
+
+	_, err := client.ListTLDs(context.Background())
+	assert.ErrorContains(t, err, "failed to acquire token lock: lock backend unavailable")
+}
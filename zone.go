@@ -0,0 +1,236 @@
+package czds
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Record represents a single resource record from a TLD zone file. Zone files distributed by CZDS follow
+// Specification 4 of the Registry Agreement, where every record is a single tab-separated line made of the owner
+// name, TTL, class, type and RDATA.
+type Record struct {
+	Name  string
+	TTL   uint32
+	Class string
+	Type  string
+	Data  string
+}
+
+// ParseRecord parses a single tab-separated zone file line into a Record.
+func ParseRecord(line string) (Record, error) {
+	parts := strings.SplitN(line, "\t", 5)
+	if len(parts) != 5 {
+		return Record{}, fmt.Errorf("expected 5 tab-separated fields, got %d", len(parts))
+	}
+
+	ttl, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to parse TTL %q: %w", parts[1], err)
+	}
+
+	return Record{
+		Name:  parts[0],
+		TTL:   uint32(ttl),
+		Class: parts[2],
+		Type:  parts[3],
+		Data:  parts[4],
+	}, nil
+}
+
+// ParseZone parses a Specification 4 zone file and returns a map of domain names to their records, where the
+// TTL, class, type and RDATA of each record are joined by commas. Parse options are applied to every line before
+// anything is allocated for it, so filtering out unneeded records considerably reduces memory and CPU usage.
+func ParseZone(r io.Reader, opts ...ParseOption) (map[string][]string, error) {
+	options := &ParseOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	domainMap := make(map[string][]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		owner, rest, ok := bytes.Cut(scanner.Bytes(), []byte{'\t'})
+		if !ok || !options.keepType(rest) {
+			continue
+		}
+
+		domain := string(owner)
+		if !options.keepOwner(domain) {
+			continue
+		}
+
+		record := strings.ReplaceAll(string(rest), "\t", ",")
+		domainMap[domain] = append(domainMap[domain], record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan zone file: %w", err)
+	}
+
+	return domainMap, nil
+}
+
+// RecordsFromZone converts the domain map returned by GetZoneFile back into records. The records are ordered by
+// owner name using the RFC 4034 canonical name order, while the records of each owner keep their original order.
+func RecordsFromZone(zone map[string][]string) ([]Record, error) {
+	names := make([]string, 0, len(zone))
+	for name := range zone {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, compareNames)
+
+	var records []Record
+	for _, name := range names {
+		for _, value := range zone[name] {
+			parts := strings.SplitN(value, ",", 4)
+			if len(parts) != 4 {
+				return nil, fmt.Errorf("malformed record %q for %s", value, name)
+			}
+
+			rec, err := ParseRecord(name + "\t" + strings.Join(parts, "\t"))
+			if err != nil {
+				return nil, fmt.Errorf("malformed record %q for %s: %w", value, name, err)
+			}
+			records = append(records, rec)
+		}
+	}
+
+	return records, nil
+}
+
+// String returns the record as a single tab-separated zone file line.
+func (r Record) String() string {
+	return strings.Join([]string{r.Name, strconv.FormatUint(uint64(r.TTL), 10), r.Class, r.Type, r.Data}, "\t")
+}
+
+// domainNameFields lists, per record type, the RDATA fields holding domain names which have to be lower-cased and
+// fully qualified for the record to be in canonical form.
+var domainNameFields = map[string][]int{
+	"ns":    {0},
+	"cname": {0},
+	"dname": {0},
+	"ptr":   {0},
+	"mx":    {1},
+	"soa":   {0, 1},
+	"srv":   {3},
+	"nsec":  {0},
+	"rrsig": {7},
+}
+
+// canonical returns the record in the Specification 4 presentation: lower-cased and fully qualified owner name,
+// lower-cased class and type mnemonics, and lower-cased, fully qualified domain names within the RDATA.
+func (r Record) canonical() Record {
+	r.Name = canonicalName(r.Name)
+	r.Class = strings.ToLower(r.Class)
+	r.Type = strings.ToLower(r.Type)
+
+	if indexes, ok := domainNameFields[r.Type]; ok {
+		fields := strings.Fields(r.Data)
+		for _, i := range indexes {
+			if i < len(fields) {
+				fields[i] = canonicalName(fields[i])
+			}
+		}
+		r.Data = strings.Join(fields, " ")
+	}
+
+	return r
+}
+
+func canonicalName(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+// compareRecords orders records as described in RFC 4034 section 6, by owner name, then by type and finally by
+// RDATA. RDATA is compared in its presentation format rather than its wire format. SOA records are always ordered
+// first, as Specification 4 requires the zone file to start with the SOA record.
+func compareRecords(a, b Record) int {
+	if aSOA, bSOA := a.Type == "soa", b.Type == "soa"; aSOA != bSOA {
+		if aSOA {
+			return -1
+		}
+		return 1
+	}
+
+	if c := compareNames(a.Name, b.Name); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(typeCode(a.Type), typeCode(b.Type)); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Type, b.Type); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.Data, b.Data)
+}
+
+// compareNames orders domain names in the RFC 4034 canonical order, comparing their labels right to left as
+// lower-cased octet strings.
+func compareNames(a, b string) int {
+	aLabels := strings.Split(strings.TrimSuffix(strings.ToLower(a), "."), ".")
+	bLabels := strings.Split(strings.TrimSuffix(strings.ToLower(b), "."), ".")
+
+	for i, j := len(aLabels)-1, len(bLabels)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(aLabels[i], bLabels[j]); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(aLabels), len(bLabels))
+}
+
+var typeCodes = map[string]int{
+	"a":          1,
+	"ns":         2,
+	"cname":      5,
+	"soa":        6,
+	"ptr":        12,
+	"hinfo":      13,
+	"mx":         15,
+	"txt":        16,
+	"aaaa":       28,
+	"srv":        33,
+	"naptr":      35,
+	"dname":      39,
+	"ds":         43,
+	"sshfp":      44,
+	"rrsig":      46,
+	"nsec":       47,
+	"dnskey":     48,
+	"nsec3":      50,
+	"nsec3param": 51,
+	"tlsa":       52,
+	"cds":        59,
+	"cdnskey":    60,
+	"zonemd":     63,
+	"svcb":       64,
+	"https":      65,
+	"caa":        257,
+}
+
+// typeCode returns the numeric value of a lower-cased record type mnemonic, including the RFC 3597 TYPEnnn form.
+// Unknown mnemonics are ordered after every known type.
+func typeCode(mnemonic string) int {
+	if code, ok := typeCodes[mnemonic]; ok {
+		return code
+	}
+
+	if code, err := strconv.ParseUint(strings.TrimPrefix(mnemonic, "type"), 10, 16); err == nil {
+		return int(code)
+	}
+
+	return 1 << 16
+}
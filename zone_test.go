@@ -0,0 +1,98 @@
+package czds_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+const testZoneFile = `com.	86400	in	soa	a.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400
+test-1.com.	10800	in	ns	ns1.test-1.com.
+test-1.com.	10800	in	NS	test-dns-2.com.
+test-1.com.	86400	in	ds	12345 8 2 ABCDEF
+ns1.test-1.com.	10800	in	a	192.0.2.1
+test-2.com.	10800	in	ns	test-dns-3.com.`
+
+func TestParseZone(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts                    []czds.ParseOption
+		expectedZoneFileDetails map[string][]string
+	}{
+		"Success_NoOptions": {
+			expectedZoneFileDetails: map[string][]string{
+				"com.": {"86400,in,soa,a.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400"},
+				"test-1.com.": {
+					"10800,in,ns,ns1.test-1.com.",
+					"10800,in,NS,test-dns-2.com.",
+					"86400,in,ds,12345 8 2 ABCDEF",
+				},
+				"ns1.test-1.com.": {"10800,in,a,192.0.2.1"},
+				"test-2.com.":     {"10800,in,ns,test-dns-3.com."},
+			},
+		},
+		"Success_RecordTypes": {
+			opts: []czds.ParseOption{czds.RecordTypes("ns", "ds")},
+			expectedZoneFileDetails: map[string][]string{
+				"test-1.com.": {
+					"10800,in,ns,ns1.test-1.com.",
+					"10800,in,NS,test-dns-2.com.",
+					"86400,in,ds,12345 8 2 ABCDEF",
+				},
+				"test-2.com.": {"10800,in,ns,test-dns-3.com."},
+			},
+		},
+		"Success_RecordTypesAndOwnerFilter": {
+			opts: []czds.ParseOption{
+				czds.RecordTypes("ns"),
+				czds.OwnerFilter(func(owner string) bool { return strings.HasPrefix(owner, "test-2") }),
+			},
+			expectedZoneFileDetails: map[string][]string{
+				"test-2.com.": {"10800,in,ns,test-dns-3.com."},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			records, err := czds.ParseZone(strings.NewReader(testZoneFile), tc.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedZoneFileDetails, records)
+		})
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	for name, tc := range map[string]struct {
+		line           string
+		expectedRecord czds.Record
+		errAssert      assert.ErrorAssertionFunc
+	}{
+		"Success": {
+			line:           "test-1.com.\t10800\tin\ttxt\t\"hello, world\"",
+			expectedRecord: czds.Record{Name: "test-1.com.", TTL: 10800, Class: "in", Type: "txt", Data: `"hello, world"`},
+			errAssert:      assert.NoError,
+		},
+		"Fail_MissingFields": {
+			line:      "test-1.com.\t10800\tin\tns",
+			errAssert: assert.Error,
+		},
+		"Fail_InvalidTTL": {
+			line:      "test-1.com.\tten\tin\tns\ttest-dns-1.com.",
+			errAssert: assert.Error,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, err := czds.ParseRecord(tc.line)
+			tc.errAssert(t, err)
+			assert.Equal(t, tc.expectedRecord, rec)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package czds
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ZoneWriter serialises records back into a zone file following Specification 4 of the Registry Agreement. Every
+// record is written as a single tab-separated line with a fully qualified, lower-cased owner name, lower-cased
+// class and type mnemonics, and lower-cased, fully qualified domain names within the RDATA, so the output can be
+// consumed by tools such as BIND's named-checkzone or ldns.
+type ZoneWriter struct {
+	w         *bufio.Writer
+	canonical bool
+	records   []Record
+}
+
+// ZoneWriterOption configures a ZoneWriter.
+type ZoneWriterOption func(*ZoneWriter)
+
+// CanonicalOrder makes the ZoneWriter emit records in the RFC 4034 canonical order, with the SOA record first and
+// duplicate records removed. Records are buffered until Flush is called.
+func CanonicalOrder() ZoneWriterOption {
+	return func(zw *ZoneWriter) {
+		zw.canonical = true
+	}
+}
+
+// NewZoneWriter returns a new ZoneWriter writing to w. By default, records are written in the order they are
+// given. Flush must be called once all the records have been written.
+func NewZoneWriter(w io.Writer, opts ...ZoneWriterOption) *ZoneWriter {
+	zw := &ZoneWriter{w: bufio.NewWriter(w)}
+	for _, opt := range opts {
+		opt(zw)
+	}
+	return zw
+}
+
+// Write writes a single record. When the canonical order is enabled, the record is buffered until Flush is called.
+func (zw *ZoneWriter) Write(rec Record) error {
+	rec = rec.canonical()
+	if zw.canonical {
+		zw.records = append(zw.records, rec)
+		return nil
+	}
+
+	return zw.writeRecord(rec)
+}
+
+// WriteZone writes every record of the domain map returned by GetZoneFile.
+func (zw *ZoneWriter) WriteZone(zone map[string][]string) error {
+	records, err := RecordsFromZone(zone)
+	if err != nil {
+		return fmt.Errorf("failed to convert zone to records: %w", err)
+	}
+
+	for _, rec := range records {
+		if err := zw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes any buffered records and flushes the underlying writer.
+func (zw *ZoneWriter) Flush() error {
+	if zw.canonical {
+		slices.SortStableFunc(zw.records, compareRecords)
+		for i, rec := range zw.records {
+			if i > 0 && rec == zw.records[i-1] {
+				continue
+			}
+			if err := zw.writeRecord(rec); err != nil {
+				return err
+			}
+		}
+		zw.records = nil
+	}
+
+	if err := zw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush zone writer: %w", err)
+	}
+
+	return nil
+}
+
+func (zw *ZoneWriter) writeRecord(rec Record) error {
+	if _, err := zw.w.WriteString(rec.String() + "\n"); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package czds_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	czds "github.com/martinsirbe/go-icann-czds-client"
+)
+
+func TestZoneWriter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts     []czds.ZoneWriterOption
+		records  []czds.Record
+		expected string
+	}{
+		"Success_WrittenInGivenOrder": {
+			records: []czds.Record{
+				{Name: "Test-1.COM", TTL: 172800, Class: "IN", Type: "NS", Data: "Test-DNS-1.com"},
+				{Name: "com.", TTL: 86400, Class: "IN", Type: "SOA",
+					Data: "a.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400"},
+			},
+			expected: "test-1.com.\t172800\tin\tns\ttest-dns-1.com.\n" +
+				"com.\t86400\tin\tsoa\ta.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400\n",
+		},
+		"Success_CanonicalOrder": {
+			opts: []czds.ZoneWriterOption{czds.CanonicalOrder()},
+			records: []czds.Record{
+				{Name: "z.com.", TTL: 172800, Class: "in", Type: "ns", Data: "ns1.z.com."},
+				{Name: "ns1.z.com.", TTL: 172800, Class: "in", Type: "a", Data: "192.0.2.1"},
+				{Name: "a.com.", TTL: 86400, Class: "in", Type: "ds", Data: "12345 8 2 ABCDEF"},
+				{Name: "a.com.", TTL: 172800, Class: "in", Type: "ns", Data: "ns1.a.com."},
+				{Name: "com.", TTL: 172800, Class: "in", Type: "ns", Data: "a.gtld-servers.net."},
+				{Name: "com.", TTL: 86400, Class: "in", Type: "soa",
+					Data: "a.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400"},
+				{Name: "a.com.", TTL: 172800, Class: "in", Type: "ns", Data: "ns1.a.com."},
+			},
+			expected: "com.\t86400\tin\tsoa\ta.gtld-servers.net. nstld.verisign-grs.com. 1 1800 900 604800 86400\n" +
+				"com.\t172800\tin\tns\ta.gtld-servers.net.\n" +
+				"a.com.\t172800\tin\tns\tns1.a.com.\n" +
+				"a.com.\t86400\tin\tds\t12345 8 2 ABCDEF\n" +
+				"z.com.\t172800\tin\tns\tns1.z.com.\n" +
+				"ns1.z.com.\t172800\tin\ta\t192.0.2.1\n",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			zw := czds.NewZoneWriter(&buf, tc.opts...)
+			for _, rec := range tc.records {
+				require.NoError(t, zw.Write(rec))
+			}
+			require.NoError(t, zw.Flush())
+
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestZoneWriter_WriteZone(t *testing.T) {
+	zone := map[string][]string{
+		"test-2.com.": {"10800,in,ns,test-dns-3.com."},
+		"test-1.com.": {"10800,in,ns,test-dns-1.com.", "10800,in,ns,test-dns-2.com."},
+	}
+
+	var buf bytes.Buffer
+	zw := czds.NewZoneWriter(&buf)
+	require.NoError(t, zw.WriteZone(zone))
+	require.NoError(t, zw.Flush())
+
+	assert.Equal(t, "test-1.com.\t10800\tin\tns\ttest-dns-1.com.\n"+
+		"test-1.com.\t10800\tin\tns\ttest-dns-2.com.\n"+
+		"test-2.com.\t10800\tin\tns\ttest-dns-3.com.\n", buf.String())
+}